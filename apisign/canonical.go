@@ -0,0 +1,73 @@
+package apisign
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// 签名相关的请求头
+const (
+	HeaderAppKey    = "X-App-Key"
+	HeaderTimestamp = "X-Timestamp"
+	HeaderNonce     = "X-Nonce"
+	HeaderSignature = "X-Signature"
+)
+
+// CanonicalRequest 构建待签名字符串，各部分以换行分隔：
+// 请求方法、路径(为空时为 /)、按参数名排序的查询串、请求体SHA256、时间戳、随机串、应用key
+func CanonicalRequest(req *http.Request, body []byte) string {
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	sum := sha256.Sum256(body)
+	return strings.Join([]string{
+		strings.ToUpper(req.Method),
+		path,
+		req.URL.Query().Encode(),
+		hex.EncodeToString(sum[:]),
+		req.Header.Get(HeaderTimestamp),
+		req.Header.Get(HeaderNonce),
+		req.Header.Get(HeaderAppKey),
+	}, "\n")
+}
+
+// Sign 使用 HMAC-SHA256 计算签名
+func Sign(secret string, canonical string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(canonical))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// errBodyTooLarge 请求体超出限制
+var errBodyTooLarge = errors.New("request body too large")
+
+// readBody 读取请求体并重置，以便后续处理仍可读取；limit 大于0时超出限制返回 errBodyTooLarge
+func readBody(req *http.Request, limit int64) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	var r io.Reader = req.Body
+	if limit > 0 {
+		r = io.LimitReader(req.Body, limit+1)
+	}
+	body, err := io.ReadAll(r)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && int64(len(body)) > limit {
+		return nil, errBodyTooLarge
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
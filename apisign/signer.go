@@ -0,0 +1,77 @@
+package apisign
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Signer 使用应用key与密钥为请求签名
+type Signer struct {
+	appKey string
+	secret string
+}
+
+func NewSigner(appKey, secret string) *Signer {
+	return &Signer{
+		appKey: appKey,
+		secret: secret,
+	}
+}
+
+// SignRequest 为请求写入应用key、时间戳、随机串与签名头
+func (s *Signer) SignRequest(req *http.Request) error {
+	body, err := readBody(req, 0)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err = rand.Read(nonce); err != nil {
+		return err
+	}
+
+	req.Header.Set(HeaderAppKey, s.appKey)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(time.Now().Unix(), 10))
+	req.Header.Set(HeaderNonce, hex.EncodeToString(nonce))
+	req.Header.Set(HeaderSignature, Sign(s.secret, CanonicalRequest(req, body)))
+
+	return nil
+}
+
+// Transport 为发出的请求自动签名的 http.RoundTripper
+type Transport struct {
+	Signer *Signer
+	Base   http.RoundTripper // 为空时使用 http.DefaultTransport
+}
+
+func NewTransport(appKey, secret string, base http.RoundTripper) *Transport {
+	return &Transport{
+		Signer: NewSigner(appKey, secret),
+		Base:   base,
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTripper 不能修改原始请求
+	signed := req.Clone(req.Context())
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		signed.Body = body
+	}
+
+	if err := t.Signer.SignRequest(signed); err != nil {
+		return nil, err
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(signed)
+}
@@ -0,0 +1,154 @@
+package apisign
+
+import (
+	"context"
+	"crypto/hmac"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/xiaWave/go-common-module/cache"
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+// 随机串 key
+const nonceKey = "apisign:nonce:"
+
+// CredentialStore 根据应用key查找密钥，找不到时返回空字符串
+type CredentialStore interface {
+	Secret(ctx context.Context, appKey string) (string, error)
+}
+
+// StaticCredentials 固定的应用key与密钥
+type StaticCredentials map[string]string
+
+func (s StaticCredentials) Secret(ctx context.Context, appKey string) (string, error) {
+	return s[appKey], nil
+}
+
+type Options struct {
+	Window      time.Duration    // 允许的时间戳偏差，默认5分钟
+	Clock       func() time.Time // 时钟，默认 time.Now
+	MaxBodySize int64            // 验签时读取的请求体上限，默认10MB
+}
+
+type Option func(o *Options)
+
+func WithWindow(d time.Duration) Option {
+	return func(o *Options) {
+		o.Window = d
+	}
+}
+
+func WithClock(clock func() time.Time) Option {
+	return func(o *Options) {
+		o.Clock = clock
+	}
+}
+
+func WithMaxBodySize(n int64) Option {
+	return func(o *Options) {
+		o.MaxBodySize = n
+	}
+}
+
+// Verifier 校验请求签名，并通过 cache.Cache 防止随机串重放，cache 需实现 cache.Atomic
+type Verifier struct {
+	store CredentialStore
+	cache cache.Cache
+	opts  Options
+}
+
+func NewVerifier(store CredentialStore, c cache.Cache, opts ...Option) *Verifier {
+	o := Options{
+		Window:      5 * time.Minute,
+		Clock:       time.Now,
+		MaxBodySize: 10 << 20,
+	}
+	for _, f := range opts {
+		f(&o)
+	}
+
+	return &Verifier{
+		store: store,
+		cache: c,
+		opts:  o,
+	}
+}
+
+// Verify 校验请求签名，失败时返回 SignError
+func (v *Verifier) Verify(req *http.Request) error {
+	ctx := req.Context()
+	appKey := req.Header.Get(HeaderAppKey)
+	nonce := req.Header.Get(HeaderNonce)
+	signature := req.Header.Get(HeaderSignature)
+	if appKey == "" || nonce == "" || signature == "" {
+		return error_codes.InvalidSignError
+	}
+
+	ts, err := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return error_codes.InvalidSignError
+	}
+	if d := v.opts.Clock().Sub(time.Unix(ts, 0)); d > v.opts.Window || d < -v.opts.Window {
+		return error_codes.InvalidSignError
+	}
+
+	secret, err := v.store.Secret(ctx, appKey)
+	if err != nil {
+		return err
+	}
+	if secret == "" {
+		return error_codes.InvalidSignError
+	}
+
+	body, err := readBody(req, v.opts.MaxBodySize)
+	if err == errBodyTooLarge {
+		return error_codes.InvalidSignError
+	}
+	if err != nil {
+		return err
+	}
+	expected := Sign(secret, CanonicalRequest(req, body))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return error_codes.InvalidSignError
+	}
+
+	// 随机串在时间窗口内只能使用一次
+	atomic, err := cache.AsAtomic(v.cache)
+	if err != nil {
+		return error_codes.NewWithError(error_codes.CacheErr, "", err)
+	}
+	ok, err := atomic.SetNX(ctx, nonceKey+appKey+":"+nonce, 1, 2*v.opts.Window)
+	if err != nil {
+		return error_codes.NewWithError(error_codes.CacheErr, "", err)
+	}
+	if !ok {
+		return error_codes.InvalidSignError
+	}
+
+	return nil
+}
+
+// HTTPMiddleware 校验请求签名，通过后将应用key写入请求 context
+func (v *Verifier) HTTPMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := v.Verify(r); err != nil {
+				_ = error_codes.WriteJSON(w, nil, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), appKeyCtxKey{}, r.Header.Get(HeaderAppKey))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+type appKeyCtxKey struct{}
+
+// AppKeyFromContext 返回通过签名校验的应用key
+func AppKeyFromContext(ctx context.Context) (string, bool) {
+	appKey, ok := ctx.Value(appKeyCtxKey{}).(string)
+	return appKey, ok
+}
@@ -0,0 +1,239 @@
+package apisign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xiaWave/go-common-module/cache"
+)
+
+// memCache 测试用的内存 cache.Cache，不处理过期
+type memCache struct {
+	mu    sync.Mutex
+	items map[string]string
+}
+
+func newMemCache() *memCache {
+	return &memCache{items: make(map[string]string)}
+}
+
+func (c *memCache) Set(ctx context.Context, key string, val interface{}, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = fmt.Sprint(val)
+	return nil
+}
+
+func (c *memCache) SetNX(ctx context.Context, key string, val interface{}, expiration time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.items[key]; ok {
+		return false, nil
+	}
+	c.items[key] = fmt.Sprint(val)
+	return true, nil
+}
+
+func (c *memCache) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (c *memCache) Decr(ctx context.Context, key string) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (c *memCache) CompareAndDelete(ctx context.Context, key string, val string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (c *memCache) Get(ctx context.Context, key string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.items[key], nil
+}
+
+func (c *memCache) GetDel(ctx context.Context, key string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v := c.items[key]
+	delete(c.items, key)
+	return v, nil
+}
+
+func (c *memCache) Scan(ctx context.Context, key string, val interface{}) error {
+	return errors.New("not implemented")
+}
+
+func (c *memCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.items, key)
+	}
+	return nil
+}
+
+func (c *memCache) Options() cache.Options          { return cache.Options{} }
+func (c *memCache) Ping(ctx context.Context) error  { return nil }
+func (c *memCache) Close(ctx context.Context) error { return nil }
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newTestServer 启动校验签名的服务，handler 返回应用key与读取到的请求体
+func newTestServer(t *testing.T, opts ...Option) *httptest.Server {
+	t.Helper()
+	v := NewVerifier(StaticCredentials{"app": "app-secret"}, newMemCache(), opts...)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		appKey, _ := AppKeyFromContext(r.Context())
+		body, _ := io.ReadAll(r.Body)
+		_, _ = fmt.Fprintf(w, "%s:%s", appKey, body)
+	})
+
+	srv := httptest.NewServer(v.HTTPMiddleware()(handler))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newTestClient 返回自动签名的客户端，tamper 在签名后、发送前修改请求
+func newTestClient(secret string, tamper func(req *http.Request)) *http.Client {
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if tamper != nil {
+			tamper(req)
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	return &http.Client{Transport: NewTransport("app", secret, base)}
+}
+
+func post(t *testing.T, client *http.Client, url, body string) (int, string) {
+	t.Helper()
+	resp, err := client.Post(url, "text/plain", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(data)
+}
+
+func TestTransportPassesVerifier(t *testing.T) {
+	srv := newTestServer(t)
+
+	status, body := post(t, newTestClient("app-secret", nil), srv.URL+"/orders?b=2&a=1", `{"id":1}`)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", status, body)
+	}
+	// 验签后下游 handler 仍可读取完整请求体
+	if body != `app:{"id":1}` {
+		t.Fatalf("body = %s", body)
+	}
+}
+
+func TestVerifierRejects(t *testing.T) {
+	srv := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		secret string
+		tamper func(req *http.Request)
+	}{
+		{"wrong secret", "other-secret", nil},
+		{"tampered body", "app-secret", func(req *http.Request) {
+			req.Body = io.NopCloser(strings.NewReader(`{"id":2}`))
+		}},
+		{"tampered query", "app-secret", func(req *http.Request) {
+			req.URL.RawQuery = "a=1&b=3"
+		}},
+		{"tampered path", "app-secret", func(req *http.Request) {
+			req.URL.Path = "/admin"
+		}},
+		{"missing signature", "app-secret", func(req *http.Request) {
+			req.Header.Del(HeaderSignature)
+		}},
+		{"unknown app", "app-secret", func(req *http.Request) {
+			req.Header.Set(HeaderAppKey, "other")
+		}},
+	}
+	for _, tt := range tests {
+		status, body := post(t, newTestClient(tt.secret, tt.tamper), srv.URL+"/orders?a=1&b=2", `{"id":1}`)
+		if status != http.StatusUnauthorized {
+			t.Errorf("%s: status = %d, body = %s", tt.name, status, body)
+		}
+	}
+}
+
+func TestVerifierTimestampWindow(t *testing.T) {
+	skewed := func(d time.Duration) func() time.Time {
+		return func() time.Time { return time.Now().Add(d) }
+	}
+	tests := []struct {
+		name   string
+		offset time.Duration
+		status int
+	}{
+		{"inside window", 4 * time.Minute, http.StatusOK},
+		{"server ahead", 6 * time.Minute, http.StatusUnauthorized},
+		{"server behind", -6 * time.Minute, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		srv := newTestServer(t, WithWindow(5*time.Minute), WithClock(skewed(tt.offset)))
+		if status, body := post(t, newTestClient("app-secret", nil), srv.URL, "x"); status != tt.status {
+			t.Errorf("%s: status = %d, want %d, body = %s", tt.name, status, tt.status, body)
+		}
+	}
+}
+
+func TestVerifierRejectsReplay(t *testing.T) {
+	srv := newTestServer(t)
+
+	// 记录签名后的请求头，原样重放
+	var signed http.Header
+	client := newTestClient("app-secret", func(req *http.Request) {
+		signed = req.Header.Clone()
+	})
+	if status, body := post(t, client, srv.URL, "x"); status != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", status, body)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header = signed
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("replay: status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestVerifierMaxBodySize(t *testing.T) {
+	srv := newTestServer(t, WithMaxBodySize(16))
+	client := newTestClient("app-secret", nil)
+
+	if status, body := post(t, client, srv.URL, strings.Repeat("a", 16)); status != http.StatusOK {
+		t.Fatalf("body at limit: status = %d, body = %s", status, body)
+	}
+	if status, _ := post(t, client, srv.URL, strings.Repeat("a", 17)); status != http.StatusUnauthorized {
+		t.Fatalf("body over limit: status = %d, want 401", status)
+	}
+}
@@ -0,0 +1,33 @@
+package authorization
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryServerInterceptor 按gRPC方法全名校验访问要求，未配置的方法不做校验，
+// 需在 token.AuthToken.UnaryServerInterceptor 之后使用
+func UnaryServerInterceptor(requirements map[string]Requirement) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if r, ok := requirements[info.FullMethod]; ok {
+			if err := r(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor 按gRPC方法全名校验访问要求，未配置的方法不做校验，
+// 需在 token.AuthToken.StreamServerInterceptor 之后使用
+func StreamServerInterceptor(requirements map[string]Requirement) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if r, ok := requirements[info.FullMethod]; ok {
+			if err := r(ss.Context()); err != nil {
+				return err
+			}
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,67 @@
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor(map[string]Requirement{
+		"/orders.Orders/Delete": Role("admin"),
+	})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		ctx    context.Context
+		code   codes.Code
+	}{
+		{"allowed", "/orders.Orders/Delete", withClaims([]string{"admin"}, nil), codes.OK},
+		{"denied", "/orders.Orders/Delete", withClaims([]string{"clerk"}, nil), codes.PermissionDenied},
+		{"unauthenticated", "/orders.Orders/Delete", context.Background(), codes.Unauthenticated},
+		{"unlisted method", "/orders.Orders/Get", context.Background(), codes.OK},
+	}
+	for _, tt := range tests {
+		resp, err := interceptor(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+		if code := status.Code(err); code != tt.code {
+			t.Errorf("%s: code = %s, want %s", tt.name, code, tt.code)
+		}
+		if tt.code == codes.OK && resp != "ok" {
+			t.Errorf("%s: handler not called", tt.name)
+		}
+	}
+}
+
+// serverStream 只提供 context 的 grpc.ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	interceptor := StreamServerInterceptor(map[string]Requirement{
+		"/orders.Orders/Watch": Scopes("orders:read"),
+	})
+	info := &grpc.StreamServerInfo{FullMethod: "/orders.Orders/Watch"}
+	handler := func(srv interface{}, ss grpc.ServerStream) error { return nil }
+
+	err := interceptor(nil, &serverStream{ctx: withClaims(nil, nil)}, info, handler)
+	if code := status.Code(err); code != codes.PermissionDenied {
+		t.Fatalf("code = %s, want PermissionDenied", code)
+	}
+	err = interceptor(nil, &serverStream{ctx: withClaims(nil, []string{"orders:read"})}, info, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
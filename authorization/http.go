@@ -0,0 +1,35 @@
+package authorization
+
+import (
+	"net/http"
+
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+// HTTPMiddleware 校验访问要求，需在 token.AuthToken.HTTPMiddleware 之后使用
+func HTTPMiddleware(req Requirement) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := req(r.Context()); err != nil {
+				_ = error_codes.WriteJSON(w, nil, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireScopes 要求拥有全部授权范围
+func RequireScopes(scopes ...string) func(http.Handler) http.Handler {
+	return HTTPMiddleware(Scopes(scopes...))
+}
+
+// RequireRole 要求拥有任一角色
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return HTTPMiddleware(Role(roles...))
+}
+
+// RequirePermission 要求策略中的角色拥有权限
+func RequirePermission(policy *Policy, permission string) func(http.Handler) http.Handler {
+	return HTTPMiddleware(Permission(policy, permission))
+}
@@ -0,0 +1,104 @@
+package authorization
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/xiaWave/go-common-module/error_codes"
+	"github.com/xiaWave/go-common-module/token"
+)
+
+// Subject 被授权的主体
+type Subject struct {
+	UserId int64
+	Roles  []string
+	Scopes []string
+}
+
+// SubjectFromClaims 从token声明构建主体
+func SubjectFromClaims(claims *token.Claims) Subject {
+	return Subject{
+		UserId: claims.UserId,
+		Roles:  claims.Roles,
+		Scopes: claims.Scopes,
+	}
+}
+
+// ResourceRule 资源级规则，在角色权限通过后对具体资源做进一步判断，如只能修改自己的订单
+type ResourceRule func(ctx context.Context, sub Subject, resource interface{}) bool
+
+// Policy 基于角色的权限策略，权限支持 * 与 orders:* 形式的通配
+type Policy struct {
+	mu    sync.RWMutex
+	roles map[string][]string
+	rules map[string][]ResourceRule
+}
+
+func NewPolicy() *Policy {
+	return &Policy{
+		roles: make(map[string][]string),
+		rules: make(map[string][]ResourceRule),
+	}
+}
+
+// AddRole 为角色添加权限
+func (p *Policy) AddRole(role string, permissions ...string) *Policy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.roles[role] = append(p.roles[role], permissions...)
+	return p
+}
+
+// AddResourceRule 为权限添加资源级规则，所有规则都通过才允许访问
+func (p *Policy) AddResourceRule(permission string, rule ResourceRule) *Policy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rules[permission] = append(p.rules[permission], rule)
+	return p
+}
+
+// Can 主体的角色是否拥有权限
+func (p *Policy) Can(sub Subject, permission string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, role := range sub.Roles {
+		for _, granted := range p.roles[role] {
+			if matchPermission(granted, permission) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Authorize 校验主体对资源的权限，拒绝时返回 AccessDenied
+func (p *Policy) Authorize(ctx context.Context, sub Subject, permission string, resource interface{}) error {
+	if !p.Can(sub, permission) {
+		return error_codes.AccessDeniedError
+	}
+
+	p.mu.RLock()
+	rules := p.rules[permission]
+	p.mu.RUnlock()
+
+	for _, rule := range rules {
+		if !rule(ctx, sub, resource) {
+			return error_codes.AccessDeniedError
+		}
+	}
+
+	return nil
+}
+
+// matchPermission 支持 * 匹配所有权限，orders:* 匹配 orders: 前缀的权限
+func matchPermission(granted, permission string) bool {
+	if granted == "*" || granted == permission {
+		return true
+	}
+	if prefix := strings.TrimSuffix(granted, "*"); prefix != granted {
+		return strings.HasPrefix(permission, prefix)
+	}
+	return false
+}
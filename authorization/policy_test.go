@@ -0,0 +1,79 @@
+package authorization
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+func TestMatchPermission(t *testing.T) {
+	tests := []struct {
+		granted    string
+		permission string
+		want       bool
+	}{
+		{"*", "orders:read", true},
+		{"*", "users:delete", true},
+		{"orders:*", "orders:read", true},
+		{"orders:*", "orders:items:write", true},
+		{"orders:*", "orders", false},
+		{"orders:*", "users:read", false},
+		{"orders:read", "orders:read", true},
+		{"orders:read", "orders:write", false},
+		{"orders:read", "orders:reader", false},
+	}
+	for _, tt := range tests {
+		if got := matchPermission(tt.granted, tt.permission); got != tt.want {
+			t.Errorf("matchPermission(%q, %q) = %v, want %v", tt.granted, tt.permission, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyCan(t *testing.T) {
+	policy := NewPolicy().
+		AddRole("admin", "*").
+		AddRole("clerk", "orders:*").
+		AddRole("viewer", "orders:read")
+
+	tests := []struct {
+		roles      []string
+		permission string
+		want       bool
+	}{
+		{[]string{"admin"}, "users:delete", true},
+		{[]string{"clerk"}, "orders:write", true},
+		{[]string{"clerk"}, "users:read", false},
+		{[]string{"viewer"}, "orders:write", false},
+		{[]string{"viewer", "clerk"}, "orders:write", true},
+		{nil, "orders:read", false},
+	}
+	for _, tt := range tests {
+		if got := policy.Can(Subject{Roles: tt.roles}, tt.permission); got != tt.want {
+			t.Errorf("Can(%v, %q) = %v, want %v", tt.roles, tt.permission, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyResourceRule(t *testing.T) {
+	type order struct{ OwnerId int64 }
+	policy := NewPolicy().
+		AddRole("user", "orders:write").
+		AddResourceRule("orders:write", func(ctx context.Context, sub Subject, resource interface{}) bool {
+			o, ok := resource.(order)
+			return ok && o.OwnerId == sub.UserId
+		})
+
+	ctx := context.Background()
+	sub := Subject{UserId: 1, Roles: []string{"user"}}
+	if err := policy.Authorize(ctx, sub, "orders:write", order{OwnerId: 1}); err != nil {
+		t.Fatalf("own order: %v", err)
+	}
+	if err := policy.Authorize(ctx, sub, "orders:write", order{OwnerId: 2}); !errors.Is(err, error_codes.AccessDeniedError) {
+		t.Fatalf("other's order: err = %v, want AccessDenied", err)
+	}
+	if err := policy.Authorize(ctx, Subject{UserId: 1}, "orders:write", order{OwnerId: 1}); !errors.Is(err, error_codes.AccessDeniedError) {
+		t.Fatalf("no role: err = %v, want AccessDenied", err)
+	}
+}
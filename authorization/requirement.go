@@ -0,0 +1,70 @@
+package authorization
+
+import (
+	"context"
+
+	"github.com/xiaWave/go-common-module/error_codes"
+	"github.com/xiaWave/go-common-module/token"
+)
+
+// Requirement 访问要求，根据 context 中的token声明判断是否允许访问
+type Requirement func(ctx context.Context) error
+
+// Scopes 要求拥有全部授权范围
+func Scopes(scopes ...string) Requirement {
+	return func(ctx context.Context) error {
+		claims, ok := token.FromContext(ctx)
+		if !ok {
+			return error_codes.UnAuthError
+		}
+		if !containsAll(claims.Scopes, scopes) {
+			return error_codes.AccessDeniedError
+		}
+		return nil
+	}
+}
+
+// Role 要求拥有任一角色
+func Role(roles ...string) Requirement {
+	return func(ctx context.Context) error {
+		claims, ok := token.FromContext(ctx)
+		if !ok {
+			return error_codes.UnAuthError
+		}
+		if !containsAny(claims.Roles, roles) {
+			return error_codes.AccessDeniedError
+		}
+		return nil
+	}
+}
+
+// Permission 要求策略中的角色拥有权限
+func Permission(policy *Policy, permission string) Requirement {
+	return func(ctx context.Context) error {
+		claims, ok := token.FromContext(ctx)
+		if !ok {
+			return error_codes.UnAuthError
+		}
+		return policy.Authorize(ctx, SubjectFromClaims(claims), permission, nil)
+	}
+}
+
+func containsAll(values []string, targets []string) bool {
+	for _, t := range targets {
+		if !containsAny(values, []string{t}) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAny(values []string, targets []string) bool {
+	for _, v := range values {
+		for _, t := range targets {
+			if v == t {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package authorization
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/xiaWave/go-common-module/error_codes"
+	"github.com/xiaWave/go-common-module/token"
+)
+
+func withClaims(roles, scopes []string) context.Context {
+	return token.NewContext(context.Background(), &token.Claims{UserId: 1, Roles: roles, Scopes: scopes})
+}
+
+func TestRequirements(t *testing.T) {
+	policy := NewPolicy().AddRole("clerk", "orders:*")
+
+	tests := []struct {
+		name string
+		req  Requirement
+		ctx  context.Context
+		want error
+	}{
+		{"all scopes", Scopes("orders:read", "orders:write"), withClaims(nil, []string{"orders:read", "orders:write", "users:read"}), nil},
+		{"missing scope", Scopes("orders:read", "orders:write"), withClaims(nil, []string{"orders:read"}), error_codes.AccessDeniedError},
+		{"any role", Role("admin", "clerk"), withClaims([]string{"clerk"}, nil), nil},
+		{"no role", Role("admin", "clerk"), withClaims([]string{"viewer"}, nil), error_codes.AccessDeniedError},
+		{"permission", Permission(policy, "orders:read"), withClaims([]string{"clerk"}, nil), nil},
+		{"no permission", Permission(policy, "users:read"), withClaims([]string{"clerk"}, nil), error_codes.AccessDeniedError},
+		{"scopes without claims", Scopes("orders:read"), context.Background(), error_codes.UnAuthError},
+		{"role without claims", Role("clerk"), context.Background(), error_codes.UnAuthError},
+		{"permission without claims", Permission(policy, "orders:read"), context.Background(), error_codes.UnAuthError},
+	}
+	for _, tt := range tests {
+		err := tt.req(tt.ctx)
+		if tt.want == nil && err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	handler := RequireScopes("orders:read")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		status int
+	}{
+		{"allowed", withClaims(nil, []string{"orders:read"}), http.StatusNoContent},
+		{"denied", withClaims(nil, nil), http.StatusForbidden},
+		{"unauthenticated", context.Background(), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil).WithContext(tt.ctx)
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.status)
+		}
+	}
+}
@@ -2,9 +2,12 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+var ErrAtomicUnsupported = errors.New("cache does not support atomic operations")
+
 // Cache 定义cache驱动接口
 type Cache interface {
 	Set(ctx context.Context, key string, val interface{}, expiration time.Duration) error
@@ -17,6 +20,26 @@ type Cache interface {
 	Close(ctx context.Context) error
 }
 
+// Atomic cache 驱动可选支持的原子操作，用于防重放、加锁与计数，redis 驱动已实现
+type Atomic interface {
+	// SetNX key 不存在时才设置，返回是否设置成功
+	SetNX(ctx context.Context, key string, val interface{}, expiration time.Duration) (bool, error)
+	// Incr 计数加一并返回新值，key 新建时设置过期时间
+	Incr(ctx context.Context, key string, expiration time.Duration) (int64, error)
+	// Decr 计数减一并返回新值，用于撤销 Incr
+	Decr(ctx context.Context, key string) (int64, error)
+	// CompareAndDelete 值等于 val 时才删除，返回是否删除，用于释放自己持有的锁
+	CompareAndDelete(ctx context.Context, key string, val string) (bool, error)
+}
+
+// AsAtomic 返回 cache 的原子操作，不支持时返回 ErrAtomicUnsupported
+func AsAtomic(c Cache) (Atomic, error) {
+	if a, ok := c.(Atomic); ok {
+		return a, nil
+	}
+	return nil, ErrAtomicUnsupported
+}
+
 // NewCache returns a new cache.
 func NewCache(opts ...Option) Cache {
 	return newRedisCache(opts...)
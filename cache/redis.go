@@ -7,6 +7,8 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+var _ Atomic = (*redisCache)(nil)
+
 // redisCache redis cache结构体
 type redisCache struct {
 	client *redis.Client
@@ -34,6 +36,39 @@ func (p *redisCache) Set(ctx context.Context, key string, val interface{}, expir
 	return p.client.Set(ctx, key, val, expiration).Err()
 }
 
+func (p *redisCache) SetNX(ctx context.Context, key string, val interface{}, expiration time.Duration) (bool, error) {
+	return p.client.SetNX(ctx, key, val, expiration).Result()
+}
+
+// incrScript 计数与设置过期时间需原子执行，避免 key 永不过期
+var incrScript = redis.NewScript(`
+local v = redis.call("INCR", KEYS[1])
+if v == 1 and tonumber(ARGV[1]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return v
+`)
+
+func (p *redisCache) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	return incrScript.Run(ctx, p.client, []string{key}, expiration.Milliseconds()).Int64()
+}
+
+func (p *redisCache) Decr(ctx context.Context, key string) (int64, error) {
+	return p.client.Decr(ctx, key).Result()
+}
+
+var compareAndDeleteScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+func (p *redisCache) CompareAndDelete(ctx context.Context, key string, val string) (bool, error) {
+	n, err := compareAndDeleteScript.Run(ctx, p.client, []string{key}, val).Int64()
+	return n == 1, err
+}
+
 func (p *redisCache) Get(ctx context.Context, key string) (interface{}, error) {
 	val, err := p.client.Get(ctx, key).Result()
 	if err == redis.Nil {
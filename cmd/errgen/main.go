@@ -0,0 +1,73 @@
+// errgen 根据错误码定义文件生成错误码常量、错误信息字典、哨兵错误以及文档
+//
+// 用法:
+//
+//	//go:generate go run github.com/xiaWave/go-common-module/cmd/errgen -spec errors.yaml -code code.go -text text.go -meta meta.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/format"
+	"log"
+	"os"
+	"text/template"
+)
+
+var (
+	specFile    = flag.String("spec", "errors.yaml", "错误码定义文件(YAML/JSON)")
+	codeFile    = flag.String("code", "", "错误码常量输出文件")
+	textFile    = flag.String("text", "", "错误信息字典与哨兵错误输出文件")
+	metaFile    = flag.String("meta", "", "多语言、HTTP状态码与分类信息输出文件")
+	docFile     = flag.String("doc", "", "Markdown 文档输出文件")
+	openapiFile = flag.String("openapi", "", "OpenAPI 片段输出文件")
+)
+
+func main() {
+	flag.Parse()
+	log.SetFlags(0)
+	log.SetPrefix("errgen: ")
+
+	spec, err := loadSpec(*specFile)
+	if err != nil {
+		log.Fatalf("load spec %s: %v", *specFile, err)
+	}
+
+	outputs := []struct {
+		file   string
+		tpl    *template.Template
+		goCode bool
+	}{
+		{*codeFile, codeTpl, true},
+		{*textFile, textTpl, true},
+		{*metaFile, metaTpl, true},
+		{*docFile, docTpl, false},
+		{*openapiFile, openapiTpl, false},
+	}
+
+	for _, o := range outputs {
+		if o.file == "" {
+			continue
+		}
+		if err = render(o.file, o.tpl, spec, o.goCode); err != nil {
+			log.Fatalf("generate %s: %v", o.file, err)
+		}
+	}
+}
+
+func render(file string, tpl *template.Template, spec *Spec, goCode bool) error {
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, spec); err != nil {
+		return err
+	}
+
+	data := buf.Bytes()
+	if goCode {
+		var err error
+		if data, err = format.Source(data); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(file, data, 0644)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec 错误码定义文件，支持 YAML 与 JSON 格式
+type Spec struct {
+	Package       string     `yaml:"package"`        // 生成代码的包名
+	DefaultLocale string     `yaml:"default_locale"` // 默认语言，用于 codeTextDict
+	Codes         []CodeSpec `yaml:"codes"`
+}
+
+// CodeSpec 单个错误码定义
+type CodeSpec struct {
+	Name       string            `yaml:"name"`        // 常量名
+	Code       int               `yaml:"code"`        // 错误码
+	Var        string            `yaml:"var"`         // 哨兵错误变量名，为空时不生成
+	HTTPStatus int               `yaml:"http_status"` // 对应的HTTP状态码
+	Retryable  bool              `yaml:"retryable"`   // 是否可以重试
+	Temporary  bool              `yaml:"temporary"`   // 是否为临时性错误
+	Fault      string            `yaml:"fault"`       // 责任方 client/server
+	Alert      bool              `yaml:"alert"`       // 是否需要告警
+	Messages   map[string]string `yaml:"messages"`    // 各语言的错误信息
+}
+
+func loadSpec(file string) (*Spec, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON 是 YAML 的子集，统一使用 YAML 解析
+	spec := &Spec{}
+	if err = yaml.Unmarshal(data, spec); err != nil {
+		return nil, err
+	}
+
+	if err = spec.validate(); err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+func (s *Spec) validate() error {
+	if s.Package == "" {
+		return fmt.Errorf("package is required")
+	}
+	if s.DefaultLocale == "" {
+		return fmt.Errorf("default_locale is required")
+	}
+
+	names := make(map[string]bool)
+	codes := make(map[int]bool)
+	for _, c := range s.Codes {
+		if c.Name == "" {
+			return fmt.Errorf("code %d: name is required", c.Code)
+		}
+		if names[c.Name] {
+			return fmt.Errorf("duplicate name %s", c.Name)
+		}
+		if codes[c.Code] {
+			return fmt.Errorf("duplicate code %d", c.Code)
+		}
+		if _, ok := c.Messages[s.DefaultLocale]; !ok {
+			return fmt.Errorf("%s: missing %s message", c.Name, s.DefaultLocale)
+		}
+		switch c.Fault {
+		case "", "client", "server":
+		default:
+			return fmt.Errorf("%s: unknown fault %q", c.Name, c.Fault)
+		}
+		names[c.Name] = true
+		codes[c.Code] = true
+	}
+
+	return nil
+}
+
+// Locales 返回所有出现过的语言，按字母排序
+func (s *Spec) Locales() []string {
+	set := make(map[string]bool)
+	for _, c := range s.Codes {
+		for l := range c.Messages {
+			set[l] = true
+		}
+	}
+
+	locales := make([]string, 0, len(set))
+	for l := range set {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+
+	return locales
+}
+
+// Message 返回指定语言的错误信息
+func (c CodeSpec) Message(locale string) string {
+	return c.Messages[locale]
+}
+
+// FaultConst 返回责任方对应的常量名
+func (c CodeSpec) FaultConst() string {
+	switch c.Fault {
+	case "client":
+		return "FaultClient"
+	case "server":
+		return "FaultServer"
+	default:
+		return "FaultNone"
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import "text/template"
+
+const goHeader = `// Code generated by errgen from {{spec}}. DO NOT EDIT.
+
+`
+
+var funcs = template.FuncMap{
+	"spec": func() string { return *specFile },
+}
+
+var codeTpl = template.Must(template.New("code").Funcs(funcs).Parse(goHeader + `package {{.Package}}
+
+// 公共错误代码
+const (
+{{- range .Codes}}
+	{{.Name}} = {{.Code}}
+{{- end}}
+)
+`))
+
+var textTpl = template.Must(template.New("text").Funcs(funcs).Parse(goHeader + `package {{.Package}}
+
+var codeTextDict = map[interface{}]string{
+{{- range .Codes}}
+	{{.Name}}: {{printf "%q" (.Message $.DefaultLocale)}},
+{{- end}}
+}
+
+var (
+{{- range .Codes}}{{if .Var}}
+	{{.Var}} = NewWithCode({{.Name}})
+{{- end}}{{end}}
+)
+`))
+
+var metaTpl = template.Must(template.New("meta").Funcs(funcs).Parse(goHeader + `package {{.Package}}
+
+// 默认语言
+const defaultLocale = {{printf "%q" .DefaultLocale}}
+
+var codeLocaleTextDict = map[string]map[int]string{
+{{- range $l := .Locales}}
+	{{printf "%q" $l}}: {
+{{- range $c := $.Codes}}{{with index $c.Messages $l}}
+		{{$c.Name}}: {{printf "%q" .}},
+{{- end}}{{end}}
+	},
+{{- end}}
+}
+
+var codeHTTPStatusDict = map[int]int{
+{{- range .Codes}}{{if .HTTPStatus}}
+	{{.Name}}: {{.HTTPStatus}},
+{{- end}}{{end}}
+}
+
+var codeClassDict = map[int]Class{
+{{- range .Codes}}
+	{{.Name}}: {Retryable: {{.Retryable}}, Temporary: {{.Temporary}}, Fault: {{.FaultConst}}, Alert: {{.Alert}}},
+{{- end}}
+}
+`))
+
+var docTpl = template.Must(template.New("doc").Funcs(funcs).Parse(`<!-- Code generated by errgen from {{spec}}. DO NOT EDIT. -->
+
+# 错误码
+
+| 错误码 | 名称 | HTTP状态码 | 可重试 | 临时错误 | 责任方 | 告警 |{{range .Locales}} {{.}} |{{end}}
+|---|---|---|---|---|---|---|{{range .Locales}}---|{{end}}
+{{- range $c := .Codes}}
+| {{.Code}} | {{.Name}} | {{.HTTPStatus}} | {{.Retryable}} | {{.Temporary}} | {{.Fault}} | {{.Alert}} |{{range $.Locales}} {{index $c.Messages .}} |{{end}}
+{{- end}}
+`))
+
+var openapiTpl = template.Must(template.New("openapi").Funcs(funcs).Parse(`# Code generated by errgen from {{spec}}. DO NOT EDIT.
+components:
+  schemas:
+    ErrorCode:
+      type: integer
+      description: |
+        错误码
+{{- range .Codes}}
+        * {{.Code}} - {{.Name}}: {{.Message $.DefaultLocale}}
+{{- end}}
+      enum:
+{{- range .Codes}}
+        - {{.Code}}
+{{- end}}
+      x-enum-varnames:
+{{- range .Codes}}
+        - {{.Name}}
+{{- end}}
+    Response:
+      type: object
+      required: [code, message]
+      properties:
+        code:
+          $ref: '#/components/schemas/ErrorCode'
+        message:
+          type: string
+        data: {}
+        details:
+          type: array
+          items:
+            type: object
+`))
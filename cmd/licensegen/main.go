@@ -0,0 +1,136 @@
+// licensegen 生成授权签名密钥、签发授权文件以及查看当前机器指纹
+//
+// 用法:
+//
+//	licensegen keygen -out license
+//	licensegen issue -key license.key -customer acme -expires 2025-12-31 -features report,export -seats 10 -out acme.lic
+//	licensegen fingerprint
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/xiaWave/go-common-module/license"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("licensegen: ")
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "keygen":
+		err = keygen(os.Args[2:])
+	case "issue":
+		err = issue(os.Args[2:])
+	case "fingerprint":
+		err = fingerprint()
+	default:
+		usage()
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: licensegen <keygen|issue|fingerprint> [flags]")
+	os.Exit(2)
+}
+
+func keygen(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	out := fs.String("out", "license", "密钥文件前缀，生成 <out>.key 与 <out>.pub")
+	_ = fs.Parse(args)
+
+	privatePEM, publicPEM, err := license.GenerateKeyPair()
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(*out+".key", privatePEM, 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(*out+".pub", publicPEM, 0644)
+}
+
+func issue(args []string) error {
+	fs := flag.NewFlagSet("issue", flag.ExitOnError)
+	keyFile := fs.String("key", "license.key", "私钥文件")
+	id := fs.String("id", "", "授权编号，默认使用客户名称与签发时间")
+	customer := fs.String("customer", "", "客户名称")
+	expires := fs.String("expires", "", "过期日期(2006-01-02)，为空表示永久")
+	features := fs.String("features", "", "开通的功能，逗号分隔")
+	fp := fs.String("fingerprint", "", "绑定的机器指纹")
+	seats := fs.Int("seats", 0, "最大席位数，0 表示不限制")
+	out := fs.String("out", "", "授权文件，为空时输出到标准输出")
+	_ = fs.Parse(args)
+
+	if *customer == "" {
+		return fmt.Errorf("customer is required")
+	}
+
+	keyData, err := os.ReadFile(*keyFile)
+	if err != nil {
+		return err
+	}
+	privateKey, err := license.ParsePrivateKeyPEM(keyData)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	lic := license.License{
+		ID:          *id,
+		Customer:    *customer,
+		IssuedAt:    now.Unix(),
+		Fingerprint: *fp,
+		MaxSeats:    *seats,
+	}
+	if lic.ID == "" {
+		lic.ID = fmt.Sprintf("%s-%d", *customer, now.Unix())
+	}
+	if *expires != "" {
+		t, err := time.ParseInLocation("2006-01-02", *expires, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid expires: %w", err)
+		}
+		// 过期日期当天仍然有效
+		lic.ExpiresAt = t.AddDate(0, 0, 1).Unix()
+	}
+	for _, f := range strings.Split(*features, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			lic.Features = append(lic.Features, f)
+		}
+	}
+
+	data, err := license.Issue(privateKey, lic)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		fmt.Println(data)
+		return nil
+	}
+	return os.WriteFile(*out, []byte(data+"\n"), 0644)
+}
+
+func fingerprint() error {
+	fp, err := license.Fingerprint()
+	if err != nil {
+		return err
+	}
+	fmt.Println(fp)
+	return nil
+}
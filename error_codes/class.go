@@ -0,0 +1,134 @@
+package error_codes
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+)
+
+// Fault 错误责任方
+type Fault uint8
+
+const (
+	// FaultNone 无责任方，如成功
+	FaultNone Fault = iota
+	// FaultClient 客户端错误，如参数非法
+	FaultClient
+	// FaultServer 服务端错误，如数据库出错
+	FaultServer
+)
+
+// Class 错误分类信息
+type Class struct {
+	Retryable bool  // 是否可以重试
+	Temporary bool  // 是否为临时性错误
+	Fault     Fault // 责任方
+	Alert     bool  // 是否需要告警
+}
+
+var (
+	clientClass    = Class{Fault: FaultClient}
+	serverClass    = Class{Fault: FaultServer, Alert: true}
+	transientClass = Class{Retryable: true, Temporary: true, Fault: FaultServer}
+)
+
+// classMu 保护 codeClassDict，默认分类由 errgen 生成在 meta.go
+var classMu sync.RWMutex
+
+// RegisterClass 注册或覆盖错误码的分类信息
+func RegisterClass(code int, class Class) {
+	classMu.Lock()
+	defer classMu.Unlock()
+	codeClassDict[code] = class
+}
+
+// ClassOf 返回错误码的分类信息，未注册的错误码视为服务端错误
+func ClassOf(code int) Class {
+	classMu.RLock()
+	defer classMu.RUnlock()
+	if class, ok := codeClassDict[code]; ok {
+		return class
+	}
+	return serverClass
+}
+
+// Classify 返回错误链的分类信息，优先使用链中 MultiError 的整体错误码或第一个 CustomError 的错误码，
+// 其次识别 context 取消/超时以及网络超时
+func Classify(err error) Class {
+	if err == nil {
+		return Class{}
+	}
+
+	var me *MultiError
+	if errors.As(err, &me) {
+		return ClassOf(me.Code())
+	}
+
+	var ce *CustomError
+	if errors.As(err, &ce) {
+		return ClassOf(ce.Code)
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return clientClass
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return transientClass
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return transientClass
+	}
+
+	return serverClass
+}
+
+// Code 返回错误链中 MultiError 的整体错误码或第一个 CustomError 的错误码，err 为 nil 时返回 SUCCESS
+func Code(err error) int {
+	if err == nil {
+		return SUCCESS
+	}
+
+	var me *MultiError
+	if errors.As(err, &me) {
+		return me.Code()
+	}
+
+	var ce *CustomError
+	if errors.As(err, &ce) {
+		return ce.Code
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return DeadlineExceeded
+	}
+
+	return FAIL
+}
+
+// IsRetryable 错误是否可以重试
+func IsRetryable(err error) bool {
+	return Classify(err).Retryable
+}
+
+// IsTemporary 错误是否为临时性错误
+func IsTemporary(err error) bool {
+	return Classify(err).Temporary
+}
+
+// IsClientFault 错误是否由客户端引起
+func IsClientFault(err error) bool {
+	return Classify(err).Fault == FaultClient
+}
+
+// IsServerFault 错误是否由服务端引起
+func IsServerFault(err error) bool {
+	return Classify(err).Fault == FaultServer
+}
+
+// ShouldAlert 错误是否需要告警
+func ShouldAlert(err error) bool {
+	return Classify(err).Alert
+}
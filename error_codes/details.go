@@ -0,0 +1,22 @@
+package error_codes
+
+import "time"
+
+// FieldViolation 字段校验失败详情
+type FieldViolation struct {
+	Field   string `json:"field"`   // 字段路径，如 user.phone
+	Rule    string `json:"rule"`    // 违反的规则，如 required
+	Message string `json:"message"` // 描述信息
+}
+
+// RetryInfo 重试建议详情
+type RetryInfo struct {
+	RetryDelay time.Duration `json:"retry_delay"` // 建议的重试间隔
+}
+
+// ResourceInfo 资源详情
+type ResourceInfo struct {
+	ResourceType string `json:"resource_type"` // 资源类型
+	ResourceName string `json:"resource_name"` // 资源名称
+	Description  string `json:"description"`   // 描述信息
+}
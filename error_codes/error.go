@@ -2,12 +2,16 @@ package error_codes
 
 import (
 	"fmt"
+	"io"
 )
 
 type CustomError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	err     error  `json:"-"`
+	Code     int                    `json:"code"`
+	Message  string                 `json:"message"`
+	Details  []interface{}          `json:"details,omitempty"`  // 类型化的详情，如 FieldViolation、RetryInfo
+	Metadata map[string]interface{} `json:"metadata,omitempty"` // 任意结构化的附加信息
+	err      error                  `json:"-"`
+	stack    *stack                 `json:"-"`
 }
 
 func (c *CustomError) Error() string {
@@ -18,10 +22,82 @@ func (c *CustomError) Error() string {
 	return errStr
 }
 
+// Unwrap 返回被包装的原始错误
+func (c *CustomError) Unwrap() error {
+	return c.err
+}
+
+// Is 错误码相同即视为同一错误，便于与哨兵错误比较
+func (c *CustomError) Is(target error) bool {
+	t, ok := target.(*CustomError)
+	return ok && t.Code == c.Code
+}
+
+// Stack 返回创建错误时的调用栈，未开启采集时返回空字符串
+func (c *CustomError) Stack() string {
+	if c.stack == nil {
+		return ""
+	}
+	return c.stack.String()
+}
+
+// WithDetails 返回追加了详情的错误副本
+func (c *CustomError) WithDetails(details ...interface{}) *CustomError {
+	e := c.clone()
+	e.Details = append(e.Details, details...)
+	return e
+}
+
+// WithMetadata 返回追加了附加信息的错误副本
+func (c *CustomError) WithMetadata(key string, value interface{}) *CustomError {
+	e := c.clone()
+	e.Metadata[key] = value
+	return e
+}
+
+// Format 实现 fmt.Formatter，%+v 会输出详情、附加信息、调用栈以及原始错误
+func (c *CustomError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = fmt.Fprintf(s, "ErrorCode:%v ,Message:%v", c.Code, c.Message)
+			for _, d := range c.Details {
+				_, _ = fmt.Fprintf(s, "\ndetail: %+v", d)
+			}
+			for k, v := range c.Metadata {
+				_, _ = fmt.Fprintf(s, "\nmetadata: %s=%v", k, v)
+			}
+			if c.stack != nil {
+				_, _ = io.WriteString(s, c.stack.String())
+			}
+			if c.err != nil {
+				_, _ = fmt.Fprintf(s, "\ncaused by: %+v", c.err)
+			}
+			return
+		}
+		_, _ = io.WriteString(s, c.Error())
+	case 's':
+		_, _ = io.WriteString(s, c.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", c.Error())
+	}
+}
+
+func (c *CustomError) clone() *CustomError {
+	e := *c
+	e.Details = append([]interface{}(nil), c.Details...)
+	e.Metadata = make(map[string]interface{}, len(c.Metadata))
+	for k, v := range c.Metadata {
+		e.Metadata[k] = v
+	}
+	return &e
+}
+
 func New(code int, message string) error {
 	e := &CustomError{
 		Code:    code,
 		Message: message,
+		stack:   callers(),
 	}
 
 	return e
@@ -31,6 +107,7 @@ func NewWithCode(code int) error {
 	return &CustomError{
 		Code:    code,
 		Message: codeTextDict[code],
+		stack:   callers(),
 	}
 }
 
@@ -39,10 +116,11 @@ func NewWithError(code int, message string, err error) error {
 		Code:    code,
 		Message: message,
 		err:     err,
+		stack:   callers(),
 	}
 	if message == "" {
 		e.Message = codeTextDict[code]
 	}
 
-	return nil
+	return e
 }
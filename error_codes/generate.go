@@ -0,0 +1,3 @@
+package error_codes
+
+//go:generate go run ../cmd/errgen -spec errors.yaml -code code.go -text text.go -meta meta.go -doc ../docs/error_codes.md -openapi ../docs/error_codes.openapi.yaml
@@ -0,0 +1,43 @@
+package error_codes
+
+import (
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCCode 根据错误码对应的HTTP状态码返回gRPC状态码
+func GRPCCode(code int) codes.Code {
+	switch HTTPStatus(code) {
+	case http.StatusOK:
+		return codes.OK
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusMethodNotAllowed:
+		return codes.Unimplemented
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusInternalServerError:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
+
+// GRPCStatus 实现 status.FromError 所需的接口，使 CustomError 可以直接作为gRPC错误返回，
+// 原始错误不会暴露给调用方
+func (c *CustomError) GRPCStatus() *status.Status {
+	return status.New(GRPCCode(c.Code), fmt.Sprintf("ErrorCode:%v ,Message:%v", c.Code, c.Message))
+}
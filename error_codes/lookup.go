@@ -0,0 +1,21 @@
+package error_codes
+
+import "net/http"
+
+// Text 返回错误码在指定语言下的错误信息，找不到时回退到默认语言
+func Text(code int, locale string) string {
+	if dict, ok := codeLocaleTextDict[locale]; ok {
+		if text, ok := dict[code]; ok {
+			return text
+		}
+	}
+	return codeLocaleTextDict[defaultLocale][code]
+}
+
+// HTTPStatus 返回错误码对应的HTTP状态码，未定义时返回 500
+func HTTPStatus(code int) int {
+	if status, ok := codeHTTPStatusDict[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,119 @@
+// Code generated by errgen from errors.yaml. DO NOT EDIT.
+
+package error_codes
+
+// 默认语言
+const defaultLocale = "zh"
+
+var codeLocaleTextDict = map[string]map[int]string{
+	"en": {
+		SUCCESS:            "Success",
+		FAIL:               "Internal server error",
+		InvalidParam:       "Invalid request parameter",
+		UnAuth:             "Unauthorized",
+		NotFound:           "Resource not found",
+		DbErr:              "Database error",
+		CacheErr:           "Cache error",
+		CreateFileFail:     "Failed to create file",
+		SignError:          "Signature verification failed",
+		GrpcSysErr:         "System error",
+		ConfigErr:          "Configuration error",
+		Unknown:            "Unknown error",
+		DeadlineExceeded:   "Deadline exceeded",
+		AccessDenied:       "Access denied",
+		LimitExceed:        "Too many requests, please retry later",
+		MethodNotAllowed:   "Method not allowed",
+		ServiceUnavailable: "Service unavailable, please retry later",
+		TokenExpired:       "Token expired",
+		TokenInvalid:       "Invalid token",
+		TicketInvalid:      "Invalid ticket",
+		PhoneEmpty:         "Phone number is empty",
+		LicenseExpired:     "License is invalid or expired",
+		PartialFailure:     "Partial failure",
+		OTPInvalid:         "Invalid or already used one-time password",
+		VerifyCodeInvalid:  "Verification code is invalid or expired",
+	},
+	"zh": {
+		SUCCESS:            "成功",
+		FAIL:               "服务内部错误",
+		InvalidParam:       "非法请求参数",
+		UnAuth:             "无访问权限",
+		NotFound:           "找不到资源",
+		DbErr:              "数据库出错",
+		CacheErr:           "缓存出错",
+		CreateFileFail:     "创建文件失败",
+		SignError:          "签名验证失败",
+		GrpcSysErr:         "系统错误",
+		ConfigErr:          "配置错误",
+		Unknown:            "未知错误",
+		DeadlineExceeded:   "操作超时",
+		AccessDenied:       "拒绝访问",
+		LimitExceed:        "请求过多，请稍后重试",
+		MethodNotAllowed:   "方法不被允许",
+		ServiceUnavailable: "服务暂不可用，请稍后重试",
+		TokenExpired:       "TOKEN过期",
+		TokenInvalid:       "非法TOKEN",
+		TicketInvalid:      "非法Ticket",
+		PhoneEmpty:         "手机号为空",
+		LicenseExpired:     "License非法或者过期",
+		PartialFailure:     "部分操作失败",
+		OTPInvalid:         "动态验证码错误或已使用",
+		VerifyCodeInvalid:  "验证码错误或已过期",
+	},
+}
+
+var codeHTTPStatusDict = map[int]int{
+	SUCCESS:            200,
+	FAIL:               500,
+	InvalidParam:       400,
+	UnAuth:             401,
+	NotFound:           404,
+	DbErr:              500,
+	CacheErr:           500,
+	CreateFileFail:     500,
+	SignError:          401,
+	GrpcSysErr:         500,
+	ConfigErr:          500,
+	Unknown:            500,
+	DeadlineExceeded:   504,
+	AccessDenied:       403,
+	LimitExceed:        429,
+	MethodNotAllowed:   405,
+	ServiceUnavailable: 503,
+	TokenExpired:       401,
+	TokenInvalid:       401,
+	TicketInvalid:      401,
+	PhoneEmpty:         400,
+	LicenseExpired:     403,
+	PartialFailure:     207,
+	OTPInvalid:         401,
+	VerifyCodeInvalid:  400,
+}
+
+var codeClassDict = map[int]Class{
+	SUCCESS:            {Retryable: false, Temporary: false, Fault: FaultNone, Alert: false},
+	FAIL:               {Retryable: false, Temporary: false, Fault: FaultServer, Alert: true},
+	InvalidParam:       {Retryable: false, Temporary: false, Fault: FaultClient, Alert: false},
+	UnAuth:             {Retryable: false, Temporary: false, Fault: FaultClient, Alert: false},
+	NotFound:           {Retryable: false, Temporary: false, Fault: FaultClient, Alert: false},
+	DbErr:              {Retryable: false, Temporary: false, Fault: FaultServer, Alert: true},
+	CacheErr:           {Retryable: false, Temporary: false, Fault: FaultServer, Alert: true},
+	CreateFileFail:     {Retryable: false, Temporary: false, Fault: FaultServer, Alert: true},
+	SignError:          {Retryable: false, Temporary: false, Fault: FaultClient, Alert: false},
+	GrpcSysErr:         {Retryable: false, Temporary: false, Fault: FaultServer, Alert: true},
+	ConfigErr:          {Retryable: false, Temporary: false, Fault: FaultServer, Alert: true},
+	Unknown:            {Retryable: false, Temporary: false, Fault: FaultServer, Alert: true},
+	DeadlineExceeded:   {Retryable: true, Temporary: true, Fault: FaultServer, Alert: false},
+	AccessDenied:       {Retryable: false, Temporary: false, Fault: FaultClient, Alert: false},
+	LimitExceed:        {Retryable: true, Temporary: true, Fault: FaultClient, Alert: false},
+	MethodNotAllowed:   {Retryable: false, Temporary: false, Fault: FaultClient, Alert: false},
+	ServiceUnavailable: {Retryable: true, Temporary: true, Fault: FaultServer, Alert: true},
+	TokenExpired:       {Retryable: false, Temporary: false, Fault: FaultClient, Alert: false},
+	TokenInvalid:       {Retryable: false, Temporary: false, Fault: FaultClient, Alert: false},
+	TicketInvalid:      {Retryable: false, Temporary: false, Fault: FaultClient, Alert: false},
+	PhoneEmpty:         {Retryable: false, Temporary: false, Fault: FaultClient, Alert: false},
+	LicenseExpired:     {Retryable: false, Temporary: false, Fault: FaultServer, Alert: true},
+	PartialFailure:     {Retryable: false, Temporary: false, Fault: FaultServer, Alert: false},
+	OTPInvalid:         {Retryable: false, Temporary: false, Fault: FaultClient, Alert: false},
+	VerifyCodeInvalid:  {Retryable: false, Temporary: false, Fault: FaultClient, Alert: false},
+}
@@ -0,0 +1,139 @@
+package error_codes
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ItemResult 批量操作中单个条目的结果
+type ItemResult struct {
+	ID      string `json:"id"`      // 条目标识
+	Code    int    `json:"code"`    // 错误码，成功为 SUCCESS
+	Message string `json:"message"` // 错误信息
+	err     error
+}
+
+// MultiError 聚合批量操作中各条目的错误，并保留各自的错误码
+type MultiError struct {
+	mu      sync.Mutex
+	results []ItemResult
+}
+
+func NewMultiError() *MultiError {
+	return &MultiError{}
+}
+
+// Add 记录一个条目的结果，err 为 nil 表示该条目成功，可并发调用
+func (m *MultiError) Add(id string, err error) {
+	code := Code(err)
+	r := ItemResult{
+		ID:      id,
+		Code:    code,
+		Message: codeTextDict[code],
+		err:     err,
+	}
+	// 非 CustomError 的错误信息不对外暴露
+	var ce *CustomError
+	if errors.As(err, &ce) {
+		r.Message = ce.Message
+	}
+
+	m.mu.Lock()
+	m.results = append(m.results, r)
+	m.mu.Unlock()
+}
+
+// Results 返回所有条目的结果
+func (m *MultiError) Results() []ItemResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ItemResult(nil), m.results...)
+}
+
+// Errors 返回所有失败条目的错误
+func (m *MultiError) Errors() []error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var errs []error
+	for _, r := range m.results {
+		if r.err != nil {
+			errs = append(errs, r.err)
+		}
+	}
+	return errs
+}
+
+// Code 计算整体错误码：全部成功为 SUCCESS，部分失败为 PartialFailure，
+// 全部失败且错误码一致时为该错误码，否则为 FAIL
+func (m *MultiError) Code() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	failed, code := 0, SUCCESS
+	for _, r := range m.results {
+		if r.err == nil {
+			continue
+		}
+		if failed > 0 && r.Code != code {
+			code = FAIL
+		} else if failed == 0 {
+			code = r.Code
+		}
+		failed++
+	}
+
+	switch {
+	case failed == 0:
+		return SUCCESS
+	case failed < len(m.results):
+		return PartialFailure
+	default:
+		return code
+	}
+}
+
+// Err 存在失败条目时返回自身，否则返回 nil
+func (m *MultiError) Err() error {
+	if len(m.Errors()) == 0 {
+		return nil
+	}
+	return m
+}
+
+func (m *MultiError) Error() string {
+	code := m.Code()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var items []string
+	for _, r := range m.results {
+		if r.err != nil {
+			items = append(items, r.ID+": "+r.err.Error())
+		}
+	}
+	return fmt.Sprintf("ErrorCode:%v ,Message:%v %s", code, codeTextDict[code], strings.Join(items, "; "))
+}
+
+// Is 任一失败条目匹配即视为匹配
+func (m *MultiError) Is(target error) bool {
+	for _, err := range m.Errors() {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As 查找第一个可以转换为 target 的失败条目
+func (m *MultiError) As(target interface{}) bool {
+	for _, err := range m.Errors() {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
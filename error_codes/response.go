@@ -0,0 +1,66 @@
+package error_codes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Response 标准JSON响应结构
+type Response struct {
+	Code    int           `json:"code"`
+	Message string        `json:"message"`
+	Data    interface{}   `json:"data,omitempty"`
+	Details []interface{} `json:"details,omitempty"`
+}
+
+// NewResponse 根据数据和错误构建响应，err 为 nil 时返回成功响应
+func NewResponse(data interface{}, err error) *Response {
+	if err == nil {
+		return &Response{
+			Code:    SUCCESS,
+			Message: codeTextDict[SUCCESS],
+			Data:    data,
+		}
+	}
+
+	var me *MultiError
+	if errors.As(err, &me) {
+		code := me.Code()
+		results := me.Results()
+		details := make([]interface{}, 0, len(results))
+		for _, r := range results {
+			details = append(details, r)
+		}
+		return &Response{
+			Code:    code,
+			Message: codeTextDict[code],
+			Data:    data,
+			Details: details,
+		}
+	}
+
+	var ce *CustomError
+	if !errors.As(err, &ce) {
+		return &Response{
+			Code:    FAIL,
+			Message: codeTextDict[FAIL],
+			Data:    data,
+		}
+	}
+
+	return &Response{
+		Code:    ce.Code,
+		Message: ce.Message,
+		Data:    data,
+		Details: ce.Details,
+	}
+}
+
+// WriteJSON 以标准JSON响应结构写入响应，HTTP状态码由错误码决定
+func WriteJSON(w http.ResponseWriter, data interface{}, err error) error {
+	resp := NewResponse(data, err)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(HTTPStatus(resp.Code))
+	return json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,56 @@
+package error_codes
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// 调用栈最大深度
+const maxStackDepth = 32
+
+// stackEnabled 是否在创建错误时采集调用栈，默认关闭以避免性能损耗
+var stackEnabled int32
+
+// EnableStack 全局开启或关闭调用栈采集
+func EnableStack(enable bool) {
+	var v int32
+	if enable {
+		v = 1
+	}
+	atomic.StoreInt32(&stackEnabled, v)
+}
+
+// StackEnabled 当前是否开启调用栈采集
+func StackEnabled() bool {
+	return atomic.LoadInt32(&stackEnabled) == 1
+}
+
+type stack []uintptr
+
+// callers 采集构造函数调用方的调用栈
+func callers() *stack {
+	if !StackEnabled() {
+		return nil
+	}
+
+	var pcs [maxStackDepth]uintptr
+	// 跳过 runtime.Callers、callers 以及构造函数本身
+	n := runtime.Callers(3, pcs[:])
+	var st stack = pcs[0:n]
+	return &st
+}
+
+func (s *stack) String() string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(*s)
+	for {
+		frame, more := frames.Next()
+		_, _ = fmt.Fprintf(&b, "\n%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
@@ -1,3 +1,5 @@
+// Code generated by errgen from errors.yaml. DO NOT EDIT.
+
 package error_codes
 
 var codeTextDict = map[interface{}]string{
@@ -23,14 +25,24 @@ var codeTextDict = map[interface{}]string{
 	TicketInvalid:      "非法Ticket",
 	PhoneEmpty:         "手机号为空",
 	LicenseExpired:     "License非法或者过期",
+	PartialFailure:     "部分操作失败",
+	OTPInvalid:         "动态验证码错误或已使用",
+	VerifyCodeInvalid:  "验证码错误或已过期",
 }
 
 var (
-	Success            = NewWithCode(SUCCESS)
-	SystemError        = NewWithCode(FAIL)
-	InvalidParamError  = NewWithCode(InvalidParam)
-	UnAuthError        = NewWithCode(UnAuth)
-	NotFoundError      = NewWithCode(NotFound)
-	DatabaseError      = NewWithCode(DbErr)
-	InvalidTicketError = NewWithCode(TicketInvalid)
+	Success                = NewWithCode(SUCCESS)
+	SystemError            = NewWithCode(FAIL)
+	InvalidParamError      = NewWithCode(InvalidParam)
+	UnAuthError            = NewWithCode(UnAuth)
+	NotFoundError          = NewWithCode(NotFound)
+	DatabaseError          = NewWithCode(DbErr)
+	InvalidSignError       = NewWithCode(SignError)
+	AccessDeniedError      = NewWithCode(AccessDenied)
+	TokenExpiredError      = NewWithCode(TokenExpired)
+	TokenInvalidError      = NewWithCode(TokenInvalid)
+	InvalidTicketError     = NewWithCode(TicketInvalid)
+	LicenseExpiredError    = NewWithCode(LicenseExpired)
+	InvalidOTPError        = NewWithCode(OTPInvalid)
+	InvalidVerifyCodeError = NewWithCode(VerifyCodeInvalid)
 )
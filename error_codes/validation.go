@@ -0,0 +1,138 @@
+package error_codes
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationError 聚合多个字段校验失败信息
+type ValidationError struct {
+	violations []FieldViolation
+}
+
+func NewValidationError() *ValidationError {
+	return &ValidationError{}
+}
+
+// Add 添加一个字段校验失败信息
+func (v *ValidationError) Add(field, rule, message string) *ValidationError {
+	v.violations = append(v.violations, FieldViolation{
+		Field:   field,
+		Rule:    rule,
+		Message: message,
+	})
+	return v
+}
+
+// HasErrors 是否存在校验失败的字段
+func (v *ValidationError) HasErrors() bool {
+	return len(v.violations) > 0
+}
+
+// Violations 返回所有字段校验失败信息
+func (v *ValidationError) Violations() []FieldViolation {
+	return v.violations
+}
+
+func (v *ValidationError) Error() string {
+	items := make([]string, 0, len(v.violations))
+	for _, fv := range v.violations {
+		items = append(items, fv.Field+": "+fv.Message)
+	}
+	return strings.Join(items, "; ")
+}
+
+// Err 转换为 InvalidParam 错误，无校验失败时返回 nil
+func (v *ValidationError) Err() error {
+	if !v.HasErrors() {
+		return nil
+	}
+
+	details := make([]interface{}, 0, len(v.violations))
+	for _, fv := range v.violations {
+		details = append(details, fv)
+	}
+
+	return &CustomError{
+		Code:    InvalidParam,
+		Message: codeTextDict[InvalidParam],
+		Details: details,
+		stack:   callers(),
+	}
+}
+
+// 常用校验规则的提示信息，%s 为规则参数
+var ruleMessages = map[string]string{
+	"required": "不能为空",
+	"email":    "邮箱格式不正确",
+	"url":      "URL格式不正确",
+	"len":      "长度必须为%s",
+	"min":      "不能小于%s",
+	"max":      "不能大于%s",
+	"gt":       "必须大于%s",
+	"gte":      "不能小于%s",
+	"lt":       "必须小于%s",
+	"lte":      "不能大于%s",
+	"eq":       "必须等于%s",
+	"ne":       "不能等于%s",
+	"oneof":    "必须是[%s]其中之一",
+}
+
+// FromValidator 将 go-playground/validator 的校验结果转换为 InvalidParam 错误。
+// 提示信息只包含规则与参数，不暴露结构体名称；validator 需通过
+// RegisterTagNameFunc(JSONTagName) 注册后，字段路径才会使用 json 名称
+func FromValidator(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return NewWithError(InvalidParam, "", err)
+	}
+
+	v := NewValidationError()
+	for _, fe := range fieldErrs {
+		v.Add(fieldPath(fe.Namespace()), fe.Tag(), ruleMessage(fe.Tag(), fe.Param()))
+	}
+
+	return v.Err()
+}
+
+// fieldPath 去掉命名空间中最外层的结构体名称
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+// JSONTagName 使用 json 标签作为字段名，用于 validator.RegisterTagNameFunc
+func JSONTagName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	default:
+		return name
+	}
+}
+
+func ruleMessage(tag, param string) string {
+	if msg, ok := ruleMessages[tag]; ok {
+		if strings.Contains(msg, "%s") {
+			return fmt.Sprintf(msg, param)
+		}
+		return msg
+	}
+	if param == "" {
+		return fmt.Sprintf("不满足%s规则", tag)
+	}
+	return fmt.Sprintf("不满足%s=%s规则", tag, param)
+}
@@ -0,0 +1,50 @@
+package license
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"os"
+	"sort"
+	"strings"
+)
+
+// machineIDFiles 常见的机器标识文件
+var machineIDFiles = []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+
+// Fingerprint 根据机器标识与主机名计算当前机器指纹，没有机器标识时使用网卡地址代替
+func Fingerprint() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+
+	parts := []string{hostname}
+	for _, file := range machineIDFiles {
+		if data, err := os.ReadFile(file); err == nil && len(data) > 0 {
+			parts = append(parts, strings.TrimSpace(string(data)))
+			return digest(parts), nil
+		}
+	}
+
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return "", err
+	}
+	var macs []string
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagLoopback != 0 || len(iface.HardwareAddr) == 0 {
+			continue
+		}
+		macs = append(macs, iface.HardwareAddr.String())
+	}
+	sort.Strings(macs)
+	parts = append(parts, macs...)
+
+	return digest(parts), nil
+}
+
+func digest(parts []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,81 @@
+package license
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidFormat    = errors.New("invalid license format")
+	ErrInvalidSignature = errors.New("invalid license signature")
+)
+
+// License 授权文件内容
+type License struct {
+	ID          string   `json:"id"`                    // 授权编号
+	Customer    string   `json:"customer"`              // 客户名称
+	IssuedAt    int64    `json:"issued_at"`             // 签发时间戳
+	ExpiresAt   int64    `json:"expires_at"`            // 过期时间戳，0 表示永久
+	Features    []string `json:"features,omitempty"`    // 开通的功能
+	Fingerprint string   `json:"fingerprint,omitempty"` // 绑定的机器指纹，为空时不限制机器
+	MaxSeats    int      `json:"max_seats,omitempty"`   // 最大席位数，0 表示不限制
+}
+
+// Expired 在 now 时是否已过期
+func (l *License) Expired(now time.Time) bool {
+	return l.ExpiresAt > 0 && now.Unix() >= l.ExpiresAt
+}
+
+// HasFeature 是否开通了功能
+func (l *License) HasFeature(feature string) bool {
+	for _, f := range l.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+var b64 = base64.RawURLEncoding
+
+// Issue 使用 Ed25519 私钥签发授权，返回 base64(内容).base64(签名) 格式的授权串
+func Issue(privateKey ed25519.PrivateKey, lic License) (string, error) {
+	payload, err := json.Marshal(lic)
+	if err != nil {
+		return "", err
+	}
+
+	signature := ed25519.Sign(privateKey, payload)
+	return b64.EncodeToString(payload) + "." + b64.EncodeToString(signature), nil
+}
+
+// Parse 使用 Ed25519 公钥校验授权串签名并解析内容，不校验有效期与机器指纹
+func Parse(publicKey ed25519.PublicKey, data string) (*License, error) {
+	encodedPayload, encodedSig, ok := strings.Cut(strings.TrimSpace(data), ".")
+	if !ok {
+		return nil, ErrInvalidFormat
+	}
+
+	payload, err := b64.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidFormat
+	}
+	signature, err := b64.DecodeString(encodedSig)
+	if err != nil {
+		return nil, ErrInvalidFormat
+	}
+
+	if !ed25519.Verify(publicKey, payload, signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	lic := &License{}
+	if err = json.Unmarshal(payload, lic); err != nil {
+		return nil, ErrInvalidFormat
+	}
+	return lic, nil
+}
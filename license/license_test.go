@@ -0,0 +1,95 @@
+package license
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newKeyPair(t *testing.T) (ed25519.PrivateKey, ed25519.PublicKey) {
+	t.Helper()
+	privPEM, pubPEM, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, err := ParsePrivateKeyPEM(privPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ParsePublicKeyPEM(pubPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv, pub
+}
+
+func TestIssueAndParse(t *testing.T) {
+	priv, pub := newKeyPair(t)
+	lic := License{
+		ID:        "lic-1",
+		Customer:  "acme",
+		IssuedAt:  time.Now().Unix(),
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		Features:  []string{"sso", "audit"},
+		MaxSeats:  10,
+	}
+
+	data, err := Issue(priv, lic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 授权文件末尾的换行不影响解析
+	got, err := Parse(pub, data+"\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != lic.ID || got.Customer != lic.Customer || got.MaxSeats != lic.MaxSeats || !got.HasFeature("audit") {
+		t.Fatalf("license = %+v, want %+v", got, lic)
+	}
+
+	_, otherPub := newKeyPair(t)
+	if _, err = Parse(otherPub, data); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("other key: err = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestParseTampered(t *testing.T) {
+	priv, pub := newKeyPair(t)
+	data, err := Issue(priv, License{ID: "lic-1", MaxSeats: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, sig, _ := strings.Cut(data, ".")
+
+	forged, err := Issue(priv, License{ID: "lic-1", MaxSeats: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	// 翻转签名的第一个字符
+	flipped := []byte(sig)
+	if flipped[0] == 'A' {
+		flipped[0] = 'B'
+	} else {
+		flipped[0] = 'A'
+	}
+
+	tests := []struct {
+		name string
+		data string
+		want error
+	}{
+		{"tampered payload", forgedPayload + "." + sig, ErrInvalidSignature},
+		{"tampered signature", payload + "." + string(flipped), ErrInvalidSignature},
+		{"missing signature", payload, ErrInvalidFormat},
+		{"bad encoding", payload + ".***", ErrInvalidFormat},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(pub, tt.data); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package license
+
+import (
+	"context"
+	"crypto/ed25519"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+// Source 读取授权串，如从文件或配置中心读取
+type Source func() (string, error)
+
+// FileSource 从文件读取授权串，每次检查都会重新读取以支持在线替换授权文件
+func FileSource(path string) Source {
+	return func() (string, error) {
+		data, err := os.ReadFile(path)
+		return string(data), err
+	}
+}
+
+type Options struct {
+	Interval    time.Duration          // 定期检查间隔，默认1小时，不大于0时使用默认值
+	Clock       func() time.Time       // 时钟，默认 time.Now
+	Fingerprint func() (string, error) // 机器指纹，默认 Fingerprint
+	OnInvalid   func(err error)        // 检查失败时的回调
+}
+
+type Option func(o *Options)
+
+func WithInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.Interval = d
+	}
+}
+
+func WithClock(clock func() time.Time) Option {
+	return func(o *Options) {
+		o.Clock = clock
+	}
+}
+
+func WithFingerprint(fn func() (string, error)) Option {
+	return func(o *Options) {
+		o.Fingerprint = fn
+	}
+}
+
+func WithOnInvalid(fn func(err error)) Option {
+	return func(o *Options) {
+		o.OnInvalid = fn
+	}
+}
+
+// Verifier 运行时授权校验，定期重新读取并校验授权
+type Verifier struct {
+	publicKey ed25519.PublicKey
+	source    Source
+	opts      Options
+
+	mu      sync.RWMutex
+	license *License
+	err     error
+}
+
+func NewVerifier(publicKey ed25519.PublicKey, source Source, opts ...Option) *Verifier {
+	o := Options{
+		Interval:    time.Hour,
+		Clock:       time.Now,
+		Fingerprint: Fingerprint,
+	}
+	for _, f := range opts {
+		f(&o)
+	}
+	if o.Interval <= 0 {
+		o.Interval = time.Hour
+	}
+
+	return &Verifier{
+		publicKey: publicKey,
+		source:    source,
+		opts:      o,
+	}
+}
+
+// Start 立即检查一次授权，并在后台定期检查直到 ctx 结束
+func (v *Verifier) Start(ctx context.Context) error {
+	err := v.Recheck()
+
+	go func() {
+		ticker := time.NewTicker(v.opts.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = v.Recheck()
+			}
+		}
+	}()
+
+	return err
+}
+
+// Recheck 重新读取并校验授权，失败时返回 LicenseExpired
+func (v *Verifier) Recheck() error {
+	lic, err := v.load()
+
+	v.mu.Lock()
+	v.license, v.err = lic, err
+	v.mu.Unlock()
+
+	if err != nil && v.opts.OnInvalid != nil {
+		v.opts.OnInvalid(err)
+	}
+	return err
+}
+
+// Check 返回最近一次检查的结果，并校验授权在当前时间是否已过期
+func (v *Verifier) Check() error {
+	_, err := v.snapshot()
+	return err
+}
+
+// License 返回当前有效的授权，授权无效时返回 nil
+func (v *Verifier) License() *License {
+	lic, _ := v.snapshot()
+	return lic
+}
+
+// Enabled 授权有效且开通了功能
+func (v *Verifier) Enabled(feature string) bool {
+	lic, _ := v.snapshot()
+	return lic != nil && lic.HasFeature(feature)
+}
+
+// Features 返回已开通的功能，授权无效时返回空
+func (v *Verifier) Features() []string {
+	lic, _ := v.snapshot()
+	if lic == nil {
+		return nil
+	}
+	return append([]string(nil), lic.Features...)
+}
+
+// CheckSeats 校验已使用的席位数是否超过授权
+func (v *Verifier) CheckSeats(used int) error {
+	lic, err := v.snapshot()
+	if err != nil {
+		return err
+	}
+
+	if lic.MaxSeats > 0 && used > lic.MaxSeats {
+		return error_codes.New(error_codes.LicenseExpired, "超出授权席位数")
+	}
+	return nil
+}
+
+// snapshot 一次性读取当前授权与检查结果，授权无效或已过期时返回错误
+func (v *Verifier) snapshot() (*License, error) {
+	v.mu.RLock()
+	lic, err := v.license, v.err
+	v.mu.RUnlock()
+
+	if err != nil {
+		return nil, err
+	}
+	if lic == nil || lic.Expired(v.opts.Clock()) {
+		return nil, error_codes.LicenseExpiredError
+	}
+	return lic, nil
+}
+
+func (v *Verifier) load() (*License, error) {
+	data, err := v.source()
+	if err != nil {
+		return nil, error_codes.NewWithError(error_codes.LicenseExpired, "", err)
+	}
+
+	lic, err := Parse(v.publicKey, data)
+	if err != nil {
+		return nil, error_codes.NewWithError(error_codes.LicenseExpired, "", err)
+	}
+
+	if lic.Expired(v.opts.Clock()) {
+		return nil, error_codes.LicenseExpiredError
+	}
+
+	if lic.Fingerprint != "" {
+		fp, err := v.opts.Fingerprint()
+		if err != nil {
+			return nil, error_codes.NewWithError(error_codes.LicenseExpired, "", err)
+		}
+		if fp != lic.Fingerprint {
+			return nil, error_codes.New(error_codes.LicenseExpired, "授权与当前机器不匹配")
+		}
+	}
+
+	return lic, nil
+}
@@ -0,0 +1,162 @@
+package license
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+// staticSource 可在测试中替换内容的 Source
+type staticSource struct {
+	mu   sync.Mutex
+	data string
+}
+
+func (s *staticSource) set(data string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = data
+}
+
+func (s *staticSource) read() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data, nil
+}
+
+func issue(t *testing.T, priv ed25519.PrivateKey, lic License) string {
+	t.Helper()
+	data, err := Issue(priv, lic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestVerifierExpiry(t *testing.T) {
+	priv, pub := newKeyPair(t)
+	now := time.Now()
+	clock := func() time.Time { return now }
+	src := &staticSource{data: issue(t, priv, License{ID: "lic-1", ExpiresAt: now.Add(time.Hour).Unix(), Features: []string{"sso"}})}
+
+	var invalid []error
+	v := NewVerifier(pub, src.read, WithClock(clock), WithOnInvalid(func(err error) {
+		invalid = append(invalid, err)
+	}))
+	if err := v.Recheck(); err != nil {
+		t.Fatal(err)
+	}
+	if !v.Enabled("sso") || v.License() == nil {
+		t.Fatal("license not enabled")
+	}
+
+	// 两次检查之间过期同样生效
+	now = now.Add(2 * time.Hour)
+	if err := v.Check(); !errors.Is(err, error_codes.LicenseExpiredError) {
+		t.Fatalf("check after expiry: err = %v, want LicenseExpired", err)
+	}
+	if v.Enabled("sso") || v.License() != nil || v.Features() != nil {
+		t.Fatal("expired license still enabled")
+	}
+
+	if err := v.Recheck(); !errors.Is(err, error_codes.LicenseExpiredError) {
+		t.Fatalf("recheck after expiry: err = %v, want LicenseExpired", err)
+	}
+	if len(invalid) != 1 {
+		t.Fatalf("OnInvalid called %d times, want 1", len(invalid))
+	}
+}
+
+func TestVerifierFingerprint(t *testing.T) {
+	priv, pub := newKeyPair(t)
+	src := &staticSource{data: issue(t, priv, License{ID: "lic-1", Fingerprint: "machine-a"})}
+
+	tests := []struct {
+		name        string
+		fingerprint string
+		ok          bool
+	}{
+		{"same machine", "machine-a", true},
+		{"other machine", "machine-b", false},
+	}
+	for _, tt := range tests {
+		fp := tt.fingerprint
+		v := NewVerifier(pub, src.read, WithFingerprint(func() (string, error) { return fp, nil }))
+		err := v.Recheck()
+		if tt.ok && err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if !tt.ok && error_codes.Code(err) != error_codes.LicenseExpired {
+			t.Errorf("%s: err = %v, want LicenseExpired", tt.name, err)
+		}
+	}
+}
+
+func TestVerifierCheckSeats(t *testing.T) {
+	priv, pub := newKeyPair(t)
+	src := &staticSource{}
+	v := NewVerifier(pub, src.read)
+
+	// 未检查过授权时不会因 nil 授权崩溃
+	if err := v.CheckSeats(1); !errors.Is(err, error_codes.LicenseExpiredError) {
+		t.Fatalf("before check: err = %v, want LicenseExpired", err)
+	}
+
+	src.set(issue(t, priv, License{ID: "lic-1", MaxSeats: 5}))
+	if err := v.Recheck(); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.CheckSeats(5); err != nil {
+		t.Fatalf("5 seats: %v", err)
+	}
+	if err := v.CheckSeats(6); error_codes.Code(err) != error_codes.LicenseExpired {
+		t.Fatalf("6 seats: err = %v, want LicenseExpired", err)
+	}
+
+	// 不限制席位数
+	src.set(issue(t, priv, License{ID: "lic-2"}))
+	if err := v.Recheck(); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.CheckSeats(1000); err != nil {
+		t.Fatalf("unlimited seats: %v", err)
+	}
+}
+
+func TestVerifierRecheckReplacedSource(t *testing.T) {
+	priv, pub := newKeyPair(t)
+	src := &staticSource{data: issue(t, priv, License{ID: "lic-1", Features: []string{"sso"}})}
+	v := NewVerifier(pub, src.read)
+	if err := v.Recheck(); err != nil {
+		t.Fatal(err)
+	}
+
+	src.set(issue(t, priv, License{ID: "lic-2", Features: []string{"audit"}}))
+	if err := v.Recheck(); err != nil {
+		t.Fatal(err)
+	}
+	if lic := v.License(); lic == nil || lic.ID != "lic-2" || v.Enabled("sso") || !v.Enabled("audit") {
+		t.Fatalf("license = %+v, want lic-2 with audit", lic)
+	}
+
+	// 替换为无效授权后立即失效
+	src.set("garbage")
+	if err := v.Recheck(); error_codes.Code(err) != error_codes.LicenseExpired {
+		t.Fatalf("err = %v, want LicenseExpired", err)
+	}
+	if v.License() != nil {
+		t.Fatal("invalid license still active")
+	}
+}
+
+func TestVerifierZeroInterval(t *testing.T) {
+	_, pub := newKeyPair(t)
+	v := NewVerifier(pub, (&staticSource{}).read, WithInterval(0))
+	if v.opts.Interval != time.Hour {
+		t.Fatalf("interval = %s, want 1h", v.opts.Interval)
+	}
+}
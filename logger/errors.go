@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"context"
+	"errors"
+
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+// 错误相关的结构化字段名
+const (
+	ErrorCodeKey    = "error.code"
+	ErrorMessageKey = "error.message"
+	ErrorCauseKey   = "error.cause"
+	ErrorStackKey   = "error.stack"
+)
+
+// findError 返回参数中的第一个错误
+func findError(args []interface{}) error {
+	for _, arg := range args {
+		if err, ok := arg.(error); ok && err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errorFields 将错误展开为结构化字段，错误链中存在 CustomError 时输出错误码、信息、原因与调用栈
+func errorFields(err error) Fields {
+	if err == nil {
+		return nil
+	}
+
+	var ce *error_codes.CustomError
+	if !errors.As(err, &ce) {
+		return Fields{ErrorMessageKey: err.Error()}
+	}
+
+	fields := Fields{
+		ErrorCodeKey:    ce.Code,
+		ErrorMessageKey: ce.Message,
+	}
+	if cause := ce.Unwrap(); cause != nil {
+		fields[ErrorCauseKey] = cause.Error()
+	}
+	if stack := ce.Stack(); stack != "" {
+		fields[ErrorStackKey] = stack
+	}
+
+	return fields
+}
+
+// levelForError 根据错误分类返回日志级别，客户端错误为 WarnLevel，其余为 ErrorLevel
+func levelForError(err error) uint8 {
+	if error_codes.IsClientFault(err) {
+		return WarnLevel
+	}
+	return ErrorLevel
+}
+
+// LogError 按错误分类记录日志，l 未实现 ErrorLogger 时通过 WithFields 附加错误字段
+func LogError(ctx context.Context, l Logger, err error, args ...interface{}) {
+	if el, ok := l.(ErrorLogger); ok {
+		el.LogError(ctx, err, args...)
+		return
+	}
+
+	l = WithError(l, err)
+	if levelForError(err) == WarnLevel {
+		l.Warn(ctx, args...)
+	} else {
+		l.Error(ctx, args...)
+	}
+}
+
+// LogErrorf 按错误分类记录格式化日志
+func LogErrorf(ctx context.Context, l Logger, err error, format string, args ...interface{}) {
+	if el, ok := l.(ErrorLogger); ok {
+		el.LogErrorf(ctx, err, format, args...)
+		return
+	}
+
+	l = WithError(l, err)
+	if levelForError(err) == WarnLevel {
+		l.Warnf(ctx, format, args...)
+	} else {
+		l.Errorf(ctx, format, args...)
+	}
+}
+
+// WithError 返回附加了错误字段的 Logger
+func WithError(l Logger, err error) Logger {
+	if el, ok := l.(ErrorLogger); ok {
+		return el.WithError(err)
+	}
+
+	fields := errorFields(err)
+	if fields == nil {
+		return l
+	}
+	return l.WithFields(fields)
+}
+
+var (
+	_ ErrorLogger = (*zapAdapt)(nil)
+	_ ErrorLogger = (*zeroAdapt)(nil)
+)
@@ -23,6 +23,16 @@ type Logger interface {
 	WithFields(fields Fields) Logger
 }
 
+// ErrorLogger Logger 的可选扩展，内置的 zap、zerolog 实现均支持；
+// 其他实现可通过包级函数 LogError、LogErrorf、WithError 获得同样的行为
+type ErrorLogger interface {
+	// LogError 按错误分类决定日志级别：客户端错误为 Warn，服务端错误为 Error
+	LogError(ctx context.Context, err error, args ...interface{})
+	LogErrorf(ctx context.Context, err error, format string, args ...interface{})
+	// WithError 将错误展开为 error.code、error.message、error.cause、error.stack 字段
+	WithError(err error) Logger
+}
+
 func New(conf *Config) (Logger, error) {
 	switch conf.AdaptType {
 	case 2:
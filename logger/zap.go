@@ -92,62 +92,82 @@ func (z *zapAdapt) level() zapcore.Level {
 
 // Debug logs a message at DebugLevel.
 func (z *zapAdapt) Debug(ctx context.Context, args ...interface{}) {
-	z.sugar.Debug(args...)
+	z.sugared(args).Debug(args...)
 }
 
 // Info logs a message at InfoLevel.
 func (z *zapAdapt) Info(ctx context.Context, args ...interface{}) {
-	z.sugar.Info(args...)
+	z.sugared(args).Info(args...)
 }
 
 // Warn logs a message at WarnLevel.
 func (z *zapAdapt) Warn(ctx context.Context, args ...interface{}) {
-	z.sugar.Warn(args...)
+	z.sugared(args).Warn(args...)
 }
 
 // Error logs a message at ErrorLevel.
 func (z *zapAdapt) Error(ctx context.Context, args ...interface{}) {
-	z.sugar.Error(args...)
+	z.sugared(args).Error(args...)
 }
 
 // Panic logs a message at PanicLevel.
 func (z *zapAdapt) Panic(ctx context.Context, args ...interface{}) {
-	z.sugar.Panic(args...)
+	z.sugared(args).Panic(args...)
 }
 
 // Fatal logs a message at FatalLevel.
 func (z *zapAdapt) Fatal(ctx context.Context, args ...interface{}) {
-	z.sugar.Fatal(args...)
+	z.sugared(args).Fatal(args...)
 }
 
 // Debugf logs a message at DebugLevel.
 func (z *zapAdapt) Debugf(ctx context.Context, format string, args ...interface{}) {
-	z.sugar.Debugf(format, args...)
+	z.sugared(args).Debugf(format, args...)
 }
 
 // Infof logs a message at InfoLevel.
 func (z *zapAdapt) Infof(ctx context.Context, format string, args ...interface{}) {
-	z.sugar.Infof(format, args...)
+	z.sugared(args).Infof(format, args...)
 }
 
 // Warnf logs a message at WarnLevel.
 func (z *zapAdapt) Warnf(ctx context.Context, format string, args ...interface{}) {
-	z.sugar.Warnf(format, args...)
+	z.sugared(args).Warnf(format, args...)
 }
 
 // Errorf logs a message at ErrorLevel.
 func (z *zapAdapt) Errorf(ctx context.Context, format string, args ...interface{}) {
-	z.sugar.Errorf(format, args...)
+	z.sugared(args).Errorf(format, args...)
 }
 
 // Panicf logs a message at PanicLevel.
 func (z *zapAdapt) Panicf(ctx context.Context, format string, args ...interface{}) {
-	z.sugar.Panicf(format, args...)
+	z.sugared(args).Panicf(format, args...)
 }
 
 // Fatalf logs a message at FatalLevel.
 func (z *zapAdapt) Fatalf(ctx context.Context, format string, args ...interface{}) {
-	z.sugar.Fatalf(format, args)
+	z.sugared(args).Fatalf(format, args...)
+}
+
+// LogError logs a message at WarnLevel for client errors and ErrorLevel otherwise.
+func (z *zapAdapt) LogError(ctx context.Context, err error, args ...interface{}) {
+	sugar := z.withError(err).sugar
+	if levelForError(err) == WarnLevel {
+		sugar.Warn(args...)
+	} else {
+		sugar.Error(args...)
+	}
+}
+
+// LogErrorf logs a message at WarnLevel for client errors and ErrorLevel otherwise.
+func (z *zapAdapt) LogErrorf(ctx context.Context, err error, format string, args ...interface{}) {
+	sugar := z.withError(err).sugar
+	if levelForError(err) == WarnLevel {
+		sugar.Warnf(format, args...)
+	} else {
+		sugar.Errorf(format, args...)
+	}
 }
 
 // WithField adds a field to the logger.
@@ -168,6 +188,25 @@ func (z *zapAdapt) WithFields(fields Fields) Logger {
 	return &zapAdapt{conf: z.conf, logger: clone, sugar: clone.Sugar()}
 }
 
+// WithError adds the structured fields of err to the logger.
+func (z *zapAdapt) WithError(err error) Logger {
+	return z.withError(err)
+}
+
+func (z *zapAdapt) withError(err error) *zapAdapt {
+	fields := errorFields(err)
+	if fields == nil {
+		return z
+	}
+
+	return z.WithFields(fields).(*zapAdapt)
+}
+
+// sugared returns a logger carrying the fields of the first error in args.
+func (z *zapAdapt) sugared(args []interface{}) *zap.SugaredLogger {
+	return z.withError(findError(args)).sugar
+}
+
 // output returns the io.Writer to write logs to.
 func output(conf *Config) (io.Writer, error) {
 	var writer io.Writer
@@ -55,51 +55,59 @@ func (l *zeroAdapt) initialize() error {
 }
 
 func (l *zeroAdapt) Debug(ctx context.Context, args ...interface{}) {
-	l.logger.Debug().Msg(fmt.Sprint(args...))
+	l.event(l.logger.Debug(), args).Msg(fmt.Sprint(args...))
 }
 
 func (l *zeroAdapt) Info(ctx context.Context, args ...interface{}) {
-	l.logger.Info().Msg(fmt.Sprint(args...))
+	l.event(l.logger.Info(), args).Msg(fmt.Sprint(args...))
 }
 
 func (l *zeroAdapt) Warn(ctx context.Context, args ...interface{}) {
-	l.logger.Warn().Msg(fmt.Sprint(args...))
+	l.event(l.logger.Warn(), args).Msg(fmt.Sprint(args...))
 }
 
 func (l *zeroAdapt) Error(ctx context.Context, args ...interface{}) {
-	l.logger.Error().Msg(fmt.Sprint(args...))
+	l.event(l.logger.Error(), args).Msg(fmt.Sprint(args...))
 }
 
 func (l *zeroAdapt) Panic(ctx context.Context, args ...interface{}) {
-	l.logger.Panic().Msg(fmt.Sprint(args...))
+	l.event(l.logger.Panic(), args).Msg(fmt.Sprint(args...))
 }
 
 func (l *zeroAdapt) Fatal(ctx context.Context, args ...interface{}) {
-	l.logger.Fatal().Msg(fmt.Sprint(args...))
+	l.event(l.logger.Fatal(), args).Msg(fmt.Sprint(args...))
 }
 
 func (l *zeroAdapt) Debugf(ctx context.Context, format string, args ...interface{}) {
-	l.logger.Debug().Msgf(format, args...)
+	l.event(l.logger.Debug(), args).Msgf(format, args...)
 }
 
 func (l *zeroAdapt) Infof(ctx context.Context, format string, args ...interface{}) {
-	l.logger.Info().Msgf(format, args...)
+	l.event(l.logger.Info(), args).Msgf(format, args...)
 }
 
 func (l *zeroAdapt) Warnf(ctx context.Context, format string, args ...interface{}) {
-	l.logger.Warn().Msgf(format, args...)
+	l.event(l.logger.Warn(), args).Msgf(format, args...)
 }
 
 func (l *zeroAdapt) Errorf(ctx context.Context, format string, args ...interface{}) {
-	l.logger.Error().Msgf(format, args...)
+	l.event(l.logger.Error(), args).Msgf(format, args...)
 }
 
 func (l *zeroAdapt) Panicf(ctx context.Context, format string, args ...interface{}) {
-	l.logger.Panic().Msgf(format, args...)
+	l.event(l.logger.Panic(), args).Msgf(format, args...)
 }
 
 func (l *zeroAdapt) Fatalf(ctx context.Context, format string, args ...interface{}) {
-	l.logger.Fatal().Msgf(format, args...)
+	l.event(l.logger.Fatal(), args).Msgf(format, args...)
+}
+
+func (l *zeroAdapt) LogError(ctx context.Context, err error, args ...interface{}) {
+	l.errorEvent(err).Msg(fmt.Sprint(args...))
+}
+
+func (l *zeroAdapt) LogErrorf(ctx context.Context, err error, format string, args ...interface{}) {
+	l.errorEvent(err).Msgf(format, args...)
 }
 
 func (l *zeroAdapt) WithField(key string, value interface{}) Logger {
@@ -118,6 +126,36 @@ func (l *zeroAdapt) WithFields(fields Fields) Logger {
 	}
 }
 
+func (l *zeroAdapt) WithError(err error) Logger {
+	fields := errorFields(err)
+	if fields == nil {
+		return l
+	}
+	return l.WithFields(fields)
+}
+
+// event 参数中存在错误时附加错误字段
+func (l *zeroAdapt) event(e *zerolog.Event, args []interface{}) *zerolog.Event {
+	if fields := errorFields(findError(args)); fields != nil {
+		e = e.Fields(map[string]interface{}(fields))
+	}
+	return e
+}
+
+// errorEvent 按错误分类创建日志事件
+func (l *zeroAdapt) errorEvent(err error) *zerolog.Event {
+	var e *zerolog.Event
+	if levelForError(err) == WarnLevel {
+		e = l.logger.Warn()
+	} else {
+		e = l.logger.Error()
+	}
+	if fields := errorFields(err); fields != nil {
+		e = e.Fields(map[string]interface{}(fields))
+	}
+	return e
+}
+
 func (l *zeroAdapt) level() zerolog.Level {
 	var level zerolog.Level
 
@@ -11,6 +11,8 @@ import (
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/sts"
 	aliOss "github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"github.com/jinzhu/now"
+
+	"github.com/xiaWave/go-common-module/error_codes"
 )
 
 type aliClient struct {
@@ -148,14 +150,13 @@ func (p *aliClient) BatchCopy(ctx context.Context, bucketName string, copyKeys m
 		return err
 	}
 	// todo: 目前sdk不支持批量复制，采用循环复制，后面可以改为多线程复制
+	multiErr := error_codes.NewMultiError()
 	for src, dest := range copyKeys {
 		_, err = bucket.CopyObject(src, dest)
-		if err != nil {
-			return err
-		}
+		multiErr.Add(src, err)
 	}
 
-	return nil
+	return multiErr.Err()
 }
 
 // Copy 复制对象
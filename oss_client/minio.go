@@ -7,6 +7,8 @@ import (
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/xiaWave/go-common-module/error_codes"
 )
 
 type minioClient struct {
@@ -101,6 +103,7 @@ func (p *minioClient) BatchCopy(ctx context.Context, bucket string, copyKeys map
 		return err
 	}
 
+	multiErr := error_codes.NewMultiError()
 	for src, dst := range copyKeys {
 		srcOpts := minio.CopySrcOptions{
 			Bucket: bucket,
@@ -114,12 +117,10 @@ func (p *minioClient) BatchCopy(ctx context.Context, bucket string, copyKeys map
 		}
 		// Copy object call
 		_, err := client.CopyObject(ctx, dstOpts, srcOpts)
-		if err != nil {
-			return err
-		}
+		multiErr.Add(src, err)
 	}
 
-	return nil
+	return multiErr.Err()
 }
 
 func (p *minioClient) Copy(ctx context.Context, bucketName string, srcObject string, dstObject string, opts ...Option) error {
@@ -9,6 +9,8 @@ import (
 	"github.com/qiniu/go-sdk/v7/auth/qbox"
 	"github.com/qiniu/go-sdk/v7/storage"
 	"github.com/spf13/cast"
+
+	"github.com/xiaWave/go-common-module/error_codes"
 )
 
 type qiniuClient struct {
@@ -98,8 +100,10 @@ func (p *qiniuClient) BatchCopy(ctx context.Context, bucket string, copyKeys map
 
 	//每个batch的操作数量不可以超过1000个，如果总数量超过1000，需要分批发送
 	copyOps := make([]string, 0, len(copyKeys))
+	srcKeys := make([]string, 0, len(copyKeys))
 	for srcKey, destKey := range copyKeys {
 		copyOps = append(copyOps, storage.URICopy(bucket, srcKey, bucket, destKey, true))
+		srcKeys = append(srcKeys, srcKey)
 	}
 
 	rets, err := bucketManager.Batch(copyOps)
@@ -107,19 +111,16 @@ func (p *qiniuClient) BatchCopy(ctx context.Context, bucket string, copyKeys map
 		return err
 	}
 
-	var errStr string
-	for _, ret := range rets {
+	multiErr := error_codes.NewMultiError()
+	for i, ret := range rets {
 		if ret.Code != 200 {
-			errStr = ret.Data.Error
-			break
+			multiErr.Add(srcKeys[i], errors.New(ret.Data.Error))
+		} else {
+			multiErr.Add(srcKeys[i], nil)
 		}
 	}
 
-	if errStr != "" {
-		return errors.New(errStr)
-	}
-
-	return nil
+	return multiErr.Err()
 }
 
 func (p *qiniuClient) Copy(ctx context.Context, bucket string, srcObject string, dstObject string, opts ...Option) error {
@@ -0,0 +1,99 @@
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+var ErrInvalidSecret = errors.New("invalid otp secret")
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret 生成 base32 编码的随机密钥，size 为字节数，推荐20
+func GenerateSecret(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return secretEncoding.EncodeToString(b), nil
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	key, err := secretEncoding.DecodeString(strings.TrimRight(secret, "="))
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
+
+// generate 按 RFC 4226 计算计数器对应的验证码
+func generate(key []byte, counter uint64, o *Options) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(o.Algorithm.hash(), key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < o.Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", o.Digits, value%mod)
+}
+
+func equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// HOTP 基于计数器的一次性密码(RFC 4226)
+type HOTP struct {
+	opts Options
+}
+
+func NewHOTP(opts ...Option) *HOTP {
+	return &HOTP{opts: newOptions(opts...)}
+}
+
+// Generate 生成计数器对应的验证码
+func (p *HOTP) Generate(secret string, counter uint64) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return generate(key, counter, &p.opts), nil
+}
+
+// Validate 从 counter 开始向前查找匹配的验证码，成功时返回调用方需保存的下一个计数器
+func (p *HOTP) Validate(secret, code string, counter uint64) (uint64, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return counter, err
+	}
+
+	for i := uint64(0); i <= uint64(p.opts.LookAhead); i++ {
+		if equal(generate(key, counter+i, &p.opts), code) {
+			return counter + i + 1, nil
+		}
+	}
+	return counter, error_codes.InvalidOTPError
+}
+
+// URI 生成验证器应用使用的 otpauth:// 地址
+func (p *HOTP) URI(account, secret string, counter uint64) string {
+	return provisioningURI("hotp", account, secret, &p.opts, func(q map[string]string) {
+		q["counter"] = fmt.Sprint(counter)
+	})
+}
@@ -0,0 +1,55 @@
+package otp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+// rfcSecret 返回 RFC 4226、RFC 6238 测试向量中 ASCII 种子的 base32 编码
+func rfcSecret(seed string) string {
+	return secretEncoding.EncodeToString([]byte(seed))
+}
+
+// RFC 4226 Appendix D
+func TestHOTPVectors(t *testing.T) {
+	want := []string{
+		"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489",
+	}
+
+	p := NewHOTP()
+	secret := rfcSecret("12345678901234567890")
+	for counter, code := range want {
+		got, err := p.Generate(secret, uint64(counter))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != code {
+			t.Errorf("counter %d: got %s, want %s", counter, got, code)
+		}
+	}
+}
+
+func TestHOTPValidateLookAhead(t *testing.T) {
+	p := NewHOTP(WithLookAhead(2))
+	secret := rfcSecret("12345678901234567890")
+
+	// 计数器 2 的验证码在向前查找范围内，返回下一个计数器
+	next, err := p.Validate(secret, "359152", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != 3 {
+		t.Fatalf("next counter = %d, want 3", next)
+	}
+
+	// 计数器 3 超出向前查找范围
+	if _, err = p.Validate(secret, "969429", 0); !errors.Is(err, error_codes.InvalidOTPError) {
+		t.Fatalf("err = %v, want InvalidOTP", err)
+	}
+	if _, err = p.Validate("not base32!", "755224", 0); !errors.Is(err, ErrInvalidSecret) {
+		t.Fatalf("err = %v, want ErrInvalidSecret", err)
+	}
+}
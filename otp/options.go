@@ -0,0 +1,116 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"time"
+
+	"github.com/xiaWave/go-common-module/cache"
+)
+
+// Algorithm HMAC 哈希算法
+type Algorithm string
+
+const (
+	AlgorithmSHA1   Algorithm = "SHA1"
+	AlgorithmSHA256 Algorithm = "SHA256"
+	AlgorithmSHA512 Algorithm = "SHA512"
+)
+
+func (a Algorithm) hash() func() hash.Hash {
+	switch a {
+	case AlgorithmSHA256:
+		return sha256.New
+	case AlgorithmSHA512:
+		return sha512.New
+	default:
+		return sha1.New
+	}
+}
+
+type Options struct {
+	Issuer    string           // 签发方，显示在验证器应用中
+	Digits    int              // 验证码位数，支持6到8位，默认6位
+	Algorithm Algorithm        // 哈希算法，默认 SHA1
+	Period    time.Duration    // TOTP 时间步长，按整秒计算，默认30秒
+	Skew      uint             // TOTP 允许前后偏移的时间步数，默认1
+	LookAhead uint             // HOTP 允许向前查找的计数器数量，默认10
+	Clock     func() time.Time // 时钟，默认 time.Now
+	Cache     cache.Cache      // 设置后验证码只能使用一次，需实现 cache.Atomic
+}
+
+type Option func(o *Options)
+
+func WithIssuer(issuer string) Option {
+	return func(o *Options) {
+		o.Issuer = issuer
+	}
+}
+
+func WithDigits(digits int) Option {
+	return func(o *Options) {
+		o.Digits = digits
+	}
+}
+
+func WithAlgorithm(alg Algorithm) Option {
+	return func(o *Options) {
+		o.Algorithm = alg
+	}
+}
+
+func WithPeriod(period time.Duration) Option {
+	return func(o *Options) {
+		o.Period = period
+	}
+}
+
+func WithSkew(skew uint) Option {
+	return func(o *Options) {
+		o.Skew = skew
+	}
+}
+
+func WithLookAhead(n uint) Option {
+	return func(o *Options) {
+		o.LookAhead = n
+	}
+}
+
+func WithClock(clock func() time.Time) Option {
+	return func(o *Options) {
+		o.Clock = clock
+	}
+}
+
+// WithCache 设置缓存用于防止验证码重放
+func WithCache(c cache.Cache) Option {
+	return func(o *Options) {
+		o.Cache = c
+	}
+}
+
+func newOptions(opts ...Option) Options {
+	o := Options{
+		Digits:    6,
+		Algorithm: AlgorithmSHA1,
+		Period:    30 * time.Second,
+		Skew:      1,
+		LookAhead: 10,
+		Clock:     time.Now,
+	}
+	for _, f := range opts {
+		f(&o)
+	}
+
+	// 超出 RFC 4226 建议范围或不足1秒的配置使用默认值
+	if o.Digits < 6 || o.Digits > 8 {
+		o.Digits = 6
+	}
+	if o.Period = o.Period.Truncate(time.Second); o.Period < time.Second {
+		o.Period = 30 * time.Second
+	}
+	return o
+}
@@ -0,0 +1,63 @@
+package otp
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// 去掉容易混淆的字符
+const recoveryAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// GenerateRecoveryCodes 生成 n 个形如 xxxxx-xxxxx 的恢复码，返回明文与需要保存的哈希
+func GenerateRecoveryCodes(n int) (codes []string, hashes []string, err error) {
+	for i := 0; i < n; i++ {
+		b, err := randomChars(10)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		code := string(b[:5]) + "-" + string(b[5:])
+		codes = append(codes, code)
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode 计算恢复码的哈希，忽略大小写、空格与连字符
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// MatchRecoveryCode 在已保存的哈希中查找恢复码，返回匹配的下标，调用方需删除该哈希使其只能使用一次
+func MatchRecoveryCode(code string, hashes []string) (int, bool) {
+	h := HashRecoveryCode(code)
+	for i, v := range hashes {
+		if equal(h, v) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// randomChars 生成随机字符，丢弃超出字母表整数倍的字节以避免取模偏差
+func randomChars(n int) ([]byte, error) {
+	limit := 256 - 256%len(recoveryAlphabet)
+	out := make([]byte, 0, n)
+	buf := make([]byte, n)
+	for len(out) < n {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		for _, c := range buf {
+			if int(c) < limit && len(out) < n {
+				out = append(out, recoveryAlphabet[int(c)%len(recoveryAlphabet)])
+			}
+		}
+	}
+	return out, nil
+}
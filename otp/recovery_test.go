@@ -0,0 +1,41 @@
+package otp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchRecoveryCode(t *testing.T) {
+	codes, hashes, err := GenerateRecoveryCodes(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != 8 || len(hashes) != 8 {
+		t.Fatalf("got %d codes, %d hashes, want 8", len(codes), len(hashes))
+	}
+
+	for i, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Fatalf("code %q, want xxxxx-xxxxx", code)
+		}
+		if idx, ok := MatchRecoveryCode(code, hashes); !ok || idx != i {
+			t.Fatalf("match %q = %d, %v, want %d", code, idx, ok, i)
+		}
+	}
+
+	// 忽略大小写、空格与连字符
+	loose := strings.ToUpper(strings.Replace(codes[3], "-", " ", 1))
+	if idx, ok := MatchRecoveryCode(loose, hashes); !ok || idx != 3 {
+		t.Fatalf("match %q = %d, %v, want 3", loose, idx, ok)
+	}
+
+	if idx, ok := MatchRecoveryCode("aaaaa-aaaaa", hashes); ok || idx != -1 {
+		t.Fatalf("unknown code matched at %d", idx)
+	}
+
+	// 调用方删除已使用的哈希后不能再次使用
+	hashes = append(hashes[:3], hashes[4:]...)
+	if _, ok := MatchRecoveryCode(codes[3], hashes); ok {
+		t.Fatal("used code matched again")
+	}
+}
@@ -0,0 +1,83 @@
+package otp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xiaWave/go-common-module/cache"
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+const usedCodeKeyPrefix = "otp:used:"
+
+// TOTP 基于时间的一次性密码(RFC 6238)
+type TOTP struct {
+	opts Options
+}
+
+func NewTOTP(opts ...Option) *TOTP {
+	return &TOTP{opts: newOptions(opts...)}
+}
+
+// Generate 生成 t 时刻的验证码
+func (p *TOTP) Generate(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return generate(key, p.step(t), &p.opts), nil
+}
+
+// Now 生成当前时刻的验证码
+func (p *TOTP) Now(secret string) (string, error) {
+	return p.Generate(secret, p.opts.Clock())
+}
+
+// Validate 校验验证码，允许前后 Skew 个时间步的偏移；设置了 Cache 时同一账号的验证码只能使用一次
+func (p *TOTP) Validate(ctx context.Context, account, secret, code string) error {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return err
+	}
+
+	current := p.step(p.opts.Clock())
+	skew := uint64(p.opts.Skew)
+	for step := current - skew; step <= current+skew; step++ {
+		if !equal(generate(key, step, &p.opts), code) {
+			continue
+		}
+
+		if p.opts.Cache == nil {
+			return nil
+		}
+
+		// 有效期覆盖整个偏移窗口，窗口过后验证码本身已失效
+		ttl := p.opts.Period * time.Duration(2*skew+1)
+		atomic, err := cache.AsAtomic(p.opts.Cache)
+		if err != nil {
+			return error_codes.NewWithError(error_codes.CacheErr, "", err)
+		}
+		ok, err := atomic.SetNX(ctx, fmt.Sprintf("%s%s:%d", usedCodeKeyPrefix, account, step), 1, ttl)
+		if err != nil {
+			return error_codes.NewWithError(error_codes.CacheErr, "", err)
+		}
+		if !ok {
+			return error_codes.InvalidOTPError
+		}
+		return nil
+	}
+
+	return error_codes.InvalidOTPError
+}
+
+// URI 生成验证器应用使用的 otpauth:// 地址
+func (p *TOTP) URI(account, secret string) string {
+	return provisioningURI("totp", account, secret, &p.opts, func(q map[string]string) {
+		q["period"] = fmt.Sprint(int(p.opts.Period / time.Second))
+	})
+}
+
+func (p *TOTP) step(t time.Time) uint64 {
+	return uint64(t.Unix() / int64(p.opts.Period/time.Second))
+}
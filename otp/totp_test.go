@@ -0,0 +1,200 @@
+package otp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xiaWave/go-common-module/cache"
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+// memCache 测试用的内存 cache.Cache，不处理过期
+type memCache struct {
+	mu    sync.Mutex
+	items map[string]string
+}
+
+func newMemCache() *memCache {
+	return &memCache{items: make(map[string]string)}
+}
+
+func (c *memCache) Set(ctx context.Context, key string, val interface{}, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = fmt.Sprint(val)
+	return nil
+}
+
+func (c *memCache) SetNX(ctx context.Context, key string, val interface{}, expiration time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.items[key]; ok {
+		return false, nil
+	}
+	c.items[key] = fmt.Sprint(val)
+	return true, nil
+}
+
+func (c *memCache) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (c *memCache) Decr(ctx context.Context, key string) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (c *memCache) CompareAndDelete(ctx context.Context, key string, val string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (c *memCache) Get(ctx context.Context, key string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.items[key], nil
+}
+
+func (c *memCache) GetDel(ctx context.Context, key string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v := c.items[key]
+	delete(c.items, key)
+	return v, nil
+}
+
+func (c *memCache) Scan(ctx context.Context, key string, val interface{}) error {
+	return errors.New("not implemented")
+}
+
+func (c *memCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.items, key)
+	}
+	return nil
+}
+
+func (c *memCache) Options() cache.Options          { return cache.Options{} }
+func (c *memCache) Ping(ctx context.Context) error  { return nil }
+func (c *memCache) Close(ctx context.Context) error { return nil }
+
+// RFC 6238 Appendix B
+func TestTOTPVectors(t *testing.T) {
+	seeds := map[Algorithm]string{
+		AlgorithmSHA1:   "12345678901234567890",
+		AlgorithmSHA256: "12345678901234567890123456789012",
+		AlgorithmSHA512: "1234567890123456789012345678901234567890123456789012345678901234",
+	}
+	tests := []struct {
+		unix int64
+		want map[Algorithm]string
+	}{
+		{59, map[Algorithm]string{AlgorithmSHA1: "94287082", AlgorithmSHA256: "46119246", AlgorithmSHA512: "90693936"}},
+		{1111111109, map[Algorithm]string{AlgorithmSHA1: "07081804", AlgorithmSHA256: "68084774", AlgorithmSHA512: "25091201"}},
+		{1111111111, map[Algorithm]string{AlgorithmSHA1: "14050471", AlgorithmSHA256: "67062674", AlgorithmSHA512: "99943326"}},
+		{1234567890, map[Algorithm]string{AlgorithmSHA1: "89005924", AlgorithmSHA256: "91819424", AlgorithmSHA512: "93441116"}},
+		{2000000000, map[Algorithm]string{AlgorithmSHA1: "69279037", AlgorithmSHA256: "90698825", AlgorithmSHA512: "38618901"}},
+		{20000000000, map[Algorithm]string{AlgorithmSHA1: "65353130", AlgorithmSHA256: "77737706", AlgorithmSHA512: "47863826"}},
+	}
+
+	for alg, seed := range seeds {
+		p := NewTOTP(WithDigits(8), WithAlgorithm(alg))
+		secret := rfcSecret(seed)
+		for _, tt := range tests {
+			got, err := p.Generate(secret, time.Unix(tt.unix, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want[alg] {
+				t.Errorf("%s at %d: got %s, want %s", alg, tt.unix, got, tt.want[alg])
+			}
+		}
+	}
+}
+
+func TestTOTPSkew(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1234567890, 0)
+	clock := func() time.Time { return now }
+	secret := rfcSecret("12345678901234567890")
+
+	tests := []struct {
+		name   string
+		skew   uint
+		offset time.Duration
+		valid  bool
+	}{
+		{"current step", 1, 0, true},
+		{"previous step", 1, -30 * time.Second, true},
+		{"next step", 1, 30 * time.Second, true},
+		{"outside window", 1, -60 * time.Second, false},
+		{"no skew", 0, -30 * time.Second, false},
+	}
+	for _, tt := range tests {
+		p := NewTOTP(WithSkew(tt.skew), WithClock(clock))
+		code, err := p.Generate(secret, now.Add(tt.offset))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = p.Validate(ctx, "alice", secret, code)
+		if tt.valid && err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if !tt.valid && !errors.Is(err, error_codes.InvalidOTPError) {
+			t.Errorf("%s: err = %v, want InvalidOTP", tt.name, err)
+		}
+	}
+}
+
+func TestTOTPReplay(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1234567890, 0)
+	p := NewTOTP(WithClock(func() time.Time { return now }), WithCache(newMemCache()))
+	secret := rfcSecret("12345678901234567890")
+
+	code, err := p.Now(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = p.Validate(ctx, "alice", secret, code); err != nil {
+		t.Fatal(err)
+	}
+
+	// 同一时间步的验证码只能使用一次
+	if err = p.Validate(ctx, "alice", secret, code); !errors.Is(err, error_codes.InvalidOTPError) {
+		t.Fatalf("replay: err = %v, want InvalidOTP", err)
+	}
+	// 按账号区分
+	if err = p.Validate(ctx, "bob", secret, code); err != nil {
+		t.Fatalf("other account: %v", err)
+	}
+
+	// 下一个时间步的验证码仍然可用
+	now = now.Add(30 * time.Second)
+	if code, err = p.Now(secret); err != nil {
+		t.Fatal(err)
+	}
+	if err = p.Validate(ctx, "alice", secret, code); err != nil {
+		t.Fatalf("next step: %v", err)
+	}
+}
+
+func TestTOTPOptionsFallback(t *testing.T) {
+	p := NewTOTP(WithDigits(10), WithPeriod(500*time.Millisecond))
+	if p.opts.Digits != 6 || p.opts.Period != 30*time.Second {
+		t.Fatalf("digits = %d, period = %s, want 6, 30s", p.opts.Digits, p.opts.Period)
+	}
+
+	code, err := p.Generate(rfcSecret("12345678901234567890"), time.Unix(59, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) != 6 {
+		t.Fatalf("code = %s, want 6 digits", code)
+	}
+}
@@ -0,0 +1,41 @@
+package otp
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+func provisioningURI(typ, account, secret string, o *Options, extra func(q map[string]string)) string {
+	params := map[string]string{
+		"secret":    strings.ToUpper(secret),
+		"algorithm": string(o.Algorithm),
+		"digits":    fmt.Sprint(o.Digits),
+	}
+	label := account
+	if o.Issuer != "" {
+		params["issuer"] = o.Issuer
+		label = o.Issuer + ":" + account
+	}
+	extra(params)
+
+	q := url.Values{}
+	for k, v := range params {
+		q.Set(k, v)
+	}
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     typ,
+		Path:     "/" + label,
+		RawQuery: q.Encode(),
+	}
+	return u.String()
+}
+
+// QRCode 将 otpauth:// 地址生成 PNG 格式的二维码，size 为图片边长像素
+func QRCode(uri string, size int) ([]byte, error) {
+	return qrcode.Encode(uri, qrcode.Medium, size)
+}
@@ -0,0 +1,39 @@
+package recovery
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/xiaWave/go-common-module/logger"
+)
+
+// UnaryServerInterceptor 捕获 unary handler 中的 panic 并返回 SystemError
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts...)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = handle(ctx, o, rec, logger.Fields{"method": info.FullMethod})
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor 捕获 stream handler 中的 panic 并返回 SystemError
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts...)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = handle(ss.Context(), o, rec, logger.Fields{"method": info.FullMethod})
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,93 @@
+package recovery
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/xiaWave/go-common-module/error_codes"
+	"github.com/xiaWave/go-common-module/logger"
+)
+
+// HTTPMiddleware 捕获 handler 中的 panic，记录日志后返回 SystemError 响应
+func HTTPMiddleware(opts ...Option) func(http.Handler) http.Handler {
+	o := newOptions(opts...)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			w := &responseWriter{ResponseWriter: rw}
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				// http.ErrAbortHandler 用于主动中断响应，交由 net/http 处理
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				err := handle(r.Context(), o, rec, logger.Fields{
+					"method": r.Method,
+					"path":   r.URL.Path,
+				})
+				// 已写出响应头时无法再返回错误响应
+				if !w.wroteHeader {
+					_ = error_codes.WriteJSON(w, nil, err)
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// responseWriter 记录是否已写出响应头
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Hijack 转发到原始 ResponseWriter，支持 websocket 等协议升级
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("recovery: ResponseWriter does not implement http.Hijacker")
+	}
+
+	conn, rw, err := h.Hijack()
+	if err == nil {
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Push 转发到原始 ResponseWriter，不支持时返回 http.ErrNotSupported
+func (w *responseWriter) Push(target string, opts *http.PushOptions) error {
+	if p, ok := w.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// Unwrap 供 http.ResponseController 访问原始 ResponseWriter
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
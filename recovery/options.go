@@ -0,0 +1,37 @@
+package recovery
+
+import (
+	"context"
+
+	"github.com/xiaWave/go-common-module/logger"
+)
+
+// AlertHook panic 告警回调，stack 为发生 panic 时的调用栈
+type AlertHook func(ctx context.Context, rec interface{}, stack []byte)
+
+type Options struct {
+	Logger logger.Logger // 记录 panic 的日志，为空时使用标准库 log
+	Hooks  []AlertHook   // 告警回调
+}
+
+type Option func(o *Options)
+
+func WithLogger(l logger.Logger) Option {
+	return func(o *Options) {
+		o.Logger = l
+	}
+}
+
+func WithAlertHook(hook AlertHook) Option {
+	return func(o *Options) {
+		o.Hooks = append(o.Hooks, hook)
+	}
+}
+
+func newOptions(opts ...Option) Options {
+	o := Options{}
+	for _, f := range opts {
+		f(&o)
+	}
+	return o
+}
@@ -0,0 +1,34 @@
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"github.com/xiaWave/go-common-module/error_codes"
+	"github.com/xiaWave/go-common-module/logger"
+)
+
+// handle 记录 panic 并触发告警，返回对外的 SystemError
+func handle(ctx context.Context, o Options, rec interface{}, fields logger.Fields) error {
+	stack := debug.Stack()
+
+	if o.Logger != nil {
+		if fields == nil {
+			fields = logger.Fields{}
+		}
+		fields["panic"] = fmt.Sprint(rec)
+		fields["stack"] = string(stack)
+		o.Logger.WithFields(fields).Errorf(ctx, "panic recovered: %v", rec)
+	} else {
+		// 未设置 Logger 时使用标准库日志，保证 panic 不会被静默吞掉
+		log.Printf("panic recovered: %v %v\n%s", rec, fields, stack)
+	}
+
+	for _, hook := range o.Hooks {
+		hook(ctx, rec, stack)
+	}
+
+	return error_codes.NewWithError(error_codes.FAIL, "", fmt.Errorf("panic: %v", rec))
+}
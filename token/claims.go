@@ -0,0 +1,164 @@
+package token
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang-jwt/jwt/v5/request"
+	"google.golang.org/grpc/metadata"
+)
+
+type Claims struct {
+	jwt.RegisteredClaims
+	UserId        int64    // 应用内部用户id
+	SignId        string   // 单点用户id
+	Roles         []string `json:",omitempty"` // 角色
+	Scopes        []string `json:",omitempty"` // 授权范围
+	DeviceId      string   `json:",omitempty"` // 登录设备id
+	SessionId     string   `json:",omitempty"` // 登录会话id，由 SessionRegistry.Login 生成
+	IssuedAtMicro int64    `json:",omitempty"` // 微秒精度的签发时间，按时间点撤销时区分同一秒内签发的token
+}
+
+// GetUserId 实现 UserClaims
+func (c Claims) GetUserId() int64 {
+	return c.UserId
+}
+
+// GetSignId 实现 SignClaims
+func (c Claims) GetSignId() string {
+	return c.SignId
+}
+
+// GetDeviceId 实现 DeviceClaims
+func (c Claims) GetDeviceId() string {
+	return c.DeviceId
+}
+
+// GetSessionId 实现 DeviceClaims
+func (c Claims) GetSessionId() string {
+	return c.SessionId
+}
+
+// GetIssuedAtMicro 实现 IssuedAtMicroClaims
+func (c Claims) GetIssuedAtMicro() int64 {
+	return c.IssuedAtMicro
+}
+
+// UserClaims 携带应用内部用户id的 Claims，按用户撤销token时使用
+type UserClaims interface {
+	GetUserId() int64
+}
+
+// SignClaims 携带单点用户id的 Claims，单点全局退出时使用
+type SignClaims interface {
+	GetSignId() string
+}
+
+// DeviceClaims 携带登录设备id与会话id的 Claims，限制并发登录时使用
+type DeviceClaims interface {
+	UserClaims
+	GetDeviceId() string
+	GetSessionId() string
+}
+
+// IssuedAtMicroClaims 携带微秒精度签发时间的 Claims，未实现时按用户、单点用户撤销只能精确到秒，
+// 撤销时间点所在这一秒内签发的token均视为已撤销
+type IssuedAtMicroClaims interface {
+	GetIssuedAtMicro() int64
+}
+
+// ClaimsPtr 约束自定义 Claims 的指针类型，自定义 Claims 需嵌入 jwt.RegisteredClaims
+type ClaimsPtr[C any] interface {
+	*C
+	jwt.Claims
+}
+
+// CreateTokenWith 使用自定义 Claims 创建token，未设置 jti 与签发时间时自动生成
+func CreateTokenWith[C any, PC ClaimsPtr[C]](p *AuthToken, claims C) (string, error) {
+	return p.createToken(PC(&claims))
+}
+
+// ParseTokenInto 解析token到自定义 Claims
+func ParseTokenInto[C any, PC ClaimsPtr[C]](ctx context.Context, p *AuthToken, tokenString string) (*C, error) {
+	result, err := ParseInto[C, PC](ctx, p, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	return result.Claims, nil
+}
+
+// ParseInto 解析token到自定义 Claims，返回声明、头信息与原始token
+func ParseInto[C any, PC ClaimsPtr[C]](ctx context.Context, p *AuthToken, tokenString string) (*ParseResult[C], error) {
+	claims := new(C)
+	token, err := p.parseToken(ctx, tokenString, PC(claims))
+	if err != nil {
+		return nil, err
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	return &ParseResult[C]{
+		Claims: claims,
+		Header: token.Header,
+		Raw:    tokenString,
+		Kid:    kid,
+	}, nil
+}
+
+// ParseFromRequestInto 按配置的来源顺序提取token并解析到自定义 Claims
+func ParseFromRequestInto[C any, PC ClaimsPtr[C]](p *AuthToken, req *http.Request) (*C, error) {
+	for _, e := range p.opts.Extractors {
+		if tokenString, ok := e.FromRequest(req); ok {
+			return ParseTokenInto[C, PC](req.Context(), p, tokenString)
+		}
+	}
+
+	return nil, request.ErrNoTokenInRequest
+}
+
+// ParseFromContextInto 按配置的来源顺序从gRPC metadata 中提取token并解析到自定义 Claims
+func ParseFromContextInto[C any, PC ClaimsPtr[C]](ctx context.Context, p *AuthToken) (*C, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, e := range p.opts.Extractors {
+		if tokenString, ok := e.FromMetadata(md); ok {
+			return ParseTokenInto[C, PC](ctx, p, tokenString)
+		}
+	}
+
+	return nil, request.ErrNoTokenInRequest
+}
+
+var registeredClaimsType = reflect.TypeOf(jwt.RegisteredClaims{})
+
+// registeredClaims 返回 Claims 中嵌入的 jwt.RegisteredClaims，claims 需为结构体指针
+func registeredClaims(claims interface{}) *jwt.RegisteredClaims {
+	if rc, ok := claims.(*jwt.RegisteredClaims); ok {
+		return rc
+	}
+
+	v := reflect.ValueOf(claims)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.Anonymous {
+			continue
+		}
+		switch {
+		case f.Type == registeredClaimsType:
+			return v.Field(i).Addr().Interface().(*jwt.RegisteredClaims)
+		case f.Type == reflect.PtrTo(registeredClaimsType) && !v.Field(i).IsNil():
+			return v.Field(i).Interface().(*jwt.RegisteredClaims)
+		}
+	}
+
+	return nil
+}
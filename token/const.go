@@ -0,0 +1,28 @@
+package token
+
+import "time"
+
+const (
+	// 默认 access token 有效期
+	defaultAccessTTL = 2 * time.Hour
+	// 默认 refresh token 有效期
+	defaultRefreshTTL = 7 * 24 * time.Hour
+	// refresh token 家族 key
+	refreshFamilyKey = "token:refresh:family:"
+	// refresh token key
+	refreshTokenKey = "token:refresh:token:"
+	// 已撤销的 jti key
+	revokedJtiKey = "token:revoked:jti:"
+	// 按用户撤销的时间点 key
+	revokedUserKey = "token:revoked:user:"
+	// 按单点用户撤销的时间点 key
+	revokedSignKey = "token:revoked:sign:"
+	// 单点登录票据 key
+	ssoTicketKey = "token:sso:ticket:"
+	// 单点登录应用会话 key
+	ssoSessionKeyPrefix = "token:sso:session:"
+	// 用户活跃会话 key
+	userSessionsKey = "token:session:user:"
+	// 用户会话修改锁 key
+	userSessionsLockKey = "token:session:lock:"
+)
@@ -0,0 +1,34 @@
+package token
+
+import "context"
+
+type claimsKey struct{}
+
+// NewContext 将 Claims 写入 context
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext 从 context 读取 Claims
+func FromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
+
+// UserIdFromContext 从 context 读取应用内部用户id
+func UserIdFromContext(ctx context.Context) (int64, bool) {
+	claims, ok := FromContext(ctx)
+	if !ok {
+		return 0, false
+	}
+	return claims.UserId, true
+}
+
+// SignIdFromContext 从 context 读取单点用户id
+func SignIdFromContext(ctx context.Context) (string, bool) {
+	claims, ok := FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	return claims.SignId, true
+}
@@ -0,0 +1,62 @@
+package token
+
+import (
+	"errors"
+
+	"github.com/go-jose/go-jose/v3"
+)
+
+var ErrEncryptionKeyRequired = errors.New("token encryption key required")
+
+// Encryption 嵌套JWT加密配置，签名后的token作为 JWE 负载加密
+type Encryption struct {
+	Algorithm  jose.KeyAlgorithm      // 密钥管理算法，如 jose.DIRECT、jose.RSA_OAEP
+	Content    jose.ContentEncryption // 内容加密算法，默认 A256GCM
+	EncryptKey interface{}            // 加密密钥，dir 为对称密钥，RSA-OAEP 为公钥
+	DecryptKey interface{}            // 解密密钥，dir 为对称密钥，RSA-OAEP 为私钥
+}
+
+// encrypt 将签名后的token加密为 JWE 紧凑格式
+func (e *Encryption) encrypt(signed string) (string, error) {
+	if e.EncryptKey == nil {
+		return "", ErrEncryptionKeyRequired
+	}
+
+	content := e.Content
+	if content == "" {
+		content = jose.A256GCM
+	}
+
+	opts := (&jose.EncrypterOptions{}).WithContentType("JWT")
+	encrypter, err := jose.NewEncrypter(content, jose.Recipient{Algorithm: e.Algorithm, Key: e.EncryptKey}, opts)
+	if err != nil {
+		return "", err
+	}
+
+	obj, err := encrypter.Encrypt([]byte(signed))
+	if err != nil {
+		return "", err
+	}
+	return obj.CompactSerialize()
+}
+
+// decrypt 解密 JWE 得到签名后的token，只接受配置的密钥管理算法
+func (e *Encryption) decrypt(tokenString string) (string, error) {
+	if e.DecryptKey == nil {
+		return "", ErrEncryptionKeyRequired
+	}
+
+	obj, err := jose.ParseEncrypted(tokenString)
+	if err != nil {
+		return "", ErrTokenInvalid
+	}
+	if obj.Header.Algorithm != string(e.Algorithm) {
+		return "", ErrTokenInvalid
+	}
+
+	signed, err := obj.Decrypt(e.DecryptKey)
+	if err != nil {
+		return "", ErrTokenInvalid
+	}
+	return string(signed), nil
+}
@@ -0,0 +1,105 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func randomKey(t *testing.T, n int) []byte {
+	t.Helper()
+	key := make([]byte, n)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestDirectEncryptionRoundTrip(t *testing.T) {
+	auth := NewAuthToken([]byte("secret"), WithDirectEncryption(randomKey(t, 32)))
+
+	tokenString, err := auth.CreateToken(newClaims(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// JWE 紧凑格式为5段
+	if n := len(strings.Split(tokenString, ".")); n != 5 {
+		t.Fatalf("token has %d parts, want 5", n)
+	}
+
+	claims, err := auth.ParseToken(tokenString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserId != 1 {
+		t.Fatalf("UserId = %d, want 1", claims.UserId)
+	}
+}
+
+func TestRSAEncryptionRoundTrip(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 签发方只持有公钥，解析方只持有私钥
+	issuer := NewAuthToken([]byte("secret"), WithRSAEncryption(&priv.PublicKey, nil))
+	parser := NewAuthToken([]byte("secret"), WithRSAEncryption(nil, priv))
+
+	tokenString, err := issuer.CreateToken(newClaims(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := parser.ParseToken(tokenString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserId != 2 {
+		t.Fatalf("UserId = %d, want 2", claims.UserId)
+	}
+
+	if _, err = issuer.ParseToken(tokenString); !errors.Is(err, ErrEncryptionKeyRequired) {
+		t.Fatalf("parse without decrypt key: err = %v, want ErrEncryptionKeyRequired", err)
+	}
+	if _, err = parser.CreateToken(newClaims(2)); !errors.Is(err, ErrEncryptionKeyRequired) {
+		t.Fatalf("create without encrypt key: err = %v, want ErrEncryptionKeyRequired", err)
+	}
+}
+
+func TestEncryptionRejects(t *testing.T) {
+	key := randomKey(t, 32)
+	auth := NewAuthToken([]byte("secret"), WithDirectEncryption(key))
+
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		issuer *AuthToken
+	}{
+		{"plain signed token", NewAuthToken([]byte("secret"))},
+		{"wrong key", NewAuthToken([]byte("secret"), WithDirectEncryption(randomKey(t, 32)))},
+		{"different alg", NewAuthToken([]byte("secret"), WithRSAEncryption(&priv.PublicKey, nil))},
+	}
+	for _, tt := range tests {
+		tokenString, err := tt.issuer.CreateToken(newClaims(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = auth.ParseToken(tokenString); !errors.Is(err, ErrTokenInvalid) {
+			t.Errorf("%s: err = %v, want ErrTokenInvalid", tt.name, err)
+		}
+	}
+}
+
+func TestEncryptionKeyRequired(t *testing.T) {
+	auth := NewAuthToken([]byte("secret"), WithEncryption(&Encryption{Algorithm: "dir"}))
+
+	if _, err := auth.CreateToken(newClaims(1)); !errors.Is(err, ErrEncryptionKeyRequired) {
+		t.Fatalf("err = %v, want ErrEncryptionKeyRequired", err)
+	}
+}
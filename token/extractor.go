@@ -0,0 +1,139 @@
+package token
+
+import (
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Extractor 从HTTP请求或gRPC metadata 中提取token，不支持的来源返回 false
+type Extractor interface {
+	FromRequest(req *http.Request) (string, bool)
+	FromMetadata(md metadata.MD) (string, bool)
+}
+
+// BearerExtractor 从 Authorization: Bearer <token> 中提取，gRPC 使用 authorization metadata
+func BearerExtractor() Extractor {
+	return bearerExtractor{}
+}
+
+// HeaderExtractor 从自定义请求头中提取，gRPC 使用同名的 metadata
+func HeaderExtractor(name string) Extractor {
+	return headerExtractor(name)
+}
+
+// CookieExtractor 从 cookie 中提取，仅支持HTTP
+func CookieExtractor(name string) Extractor {
+	return cookieExtractor(name)
+}
+
+// QueryExtractor 从URL查询参数中提取，仅支持HTTP，常用于 websocket
+func QueryExtractor(name string) Extractor {
+	return queryExtractor(name)
+}
+
+// ArgumentExtractor 从URL查询参数或表单参数中提取，仅支持HTTP，与 request.ArgumentExtractor 一致
+func ArgumentExtractor(name string) Extractor {
+	return argumentExtractor(name)
+}
+
+// MetadataExtractor 从gRPC metadata 中提取，仅支持gRPC
+func MetadataExtractor(key string) Extractor {
+	return metadataExtractor(strings.ToLower(key))
+}
+
+// defaultExtractors 与 request.OAuth2Extractor 保持一致
+func defaultExtractors() []Extractor {
+	return []Extractor{BearerExtractor(), ArgumentExtractor("access_token")}
+}
+
+type bearerExtractor struct{}
+
+func (bearerExtractor) FromRequest(req *http.Request) (string, bool) {
+	return stripBearer(req.Header.Get("Authorization"))
+}
+
+func (bearerExtractor) FromMetadata(md metadata.MD) (string, bool) {
+	for _, v := range md.Get("authorization") {
+		if token, ok := stripBearer(v); ok {
+			return token, true
+		}
+	}
+	return "", false
+}
+
+type headerExtractor string
+
+func (h headerExtractor) FromRequest(req *http.Request) (string, bool) {
+	v := req.Header.Get(string(h))
+	return v, v != ""
+}
+
+func (h headerExtractor) FromMetadata(md metadata.MD) (string, bool) {
+	return firstValue(md, strings.ToLower(string(h)))
+}
+
+type cookieExtractor string
+
+func (c cookieExtractor) FromRequest(req *http.Request) (string, bool) {
+	cookie, err := req.Cookie(string(c))
+	if err != nil || cookie.Value == "" {
+		return "", false
+	}
+	return cookie.Value, true
+}
+
+func (c cookieExtractor) FromMetadata(md metadata.MD) (string, bool) {
+	return "", false
+}
+
+type queryExtractor string
+
+func (q queryExtractor) FromRequest(req *http.Request) (string, bool) {
+	v := req.URL.Query().Get(string(q))
+	return v, v != ""
+}
+
+func (q queryExtractor) FromMetadata(md metadata.MD) (string, bool) {
+	return "", false
+}
+
+type argumentExtractor string
+
+func (a argumentExtractor) FromRequest(req *http.Request) (string, bool) {
+	// 与 request.ArgumentExtractor 一致，限制表单大小为 10MB
+	_ = req.ParseMultipartForm(10e6)
+	v := req.Form.Get(string(a))
+	return v, v != ""
+}
+
+func (a argumentExtractor) FromMetadata(md metadata.MD) (string, bool) {
+	return "", false
+}
+
+type metadataExtractor string
+
+func (m metadataExtractor) FromRequest(req *http.Request) (string, bool) {
+	return "", false
+}
+
+func (m metadataExtractor) FromMetadata(md metadata.MD) (string, bool) {
+	return firstValue(md, string(m))
+}
+
+func stripBearer(v string) (string, bool) {
+	if len(v) > 7 && strings.EqualFold(v[:7], "bearer ") {
+		return v[7:], true
+	}
+	return "", false
+}
+
+func firstValue(md metadata.MD, key string) (string, bool) {
+	for _, v := range md.Get(key) {
+		if v != "" {
+			return v, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,52 @@
+package token
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryServerInterceptor 认证gRPC请求中的token，并将 Claims 写入 context
+func (p *AuthToken) UnaryServerInterceptor(opts ...MiddlewareOption) grpc.UnaryServerInterceptor {
+	o := newMiddlewareOptions(opts...)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if matchAny(o.SkipMethods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		claims, err := p.ParseFromContext(ctx)
+		if err != nil {
+			return nil, AuthError(err)
+		}
+
+		return handler(NewContext(ctx, claims), req)
+	}
+}
+
+// StreamServerInterceptor 认证gRPC流请求中的token，并将 Claims 写入流的 context
+func (p *AuthToken) StreamServerInterceptor(opts ...MiddlewareOption) grpc.StreamServerInterceptor {
+	o := newMiddlewareOptions(opts...)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if matchAny(o.SkipMethods, info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		claims, err := p.ParseFromContext(ss.Context())
+		if err != nil {
+			return AuthError(err)
+		}
+
+		return handler(srv, &authServerStream{ServerStream: ss, ctx: NewContext(ss.Context(), claims)})
+	}
+}
+
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
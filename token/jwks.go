@@ -0,0 +1,196 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/sync/singleflight"
+)
+
+// JWKSHandler 以 JWKS 格式发布密钥集合中的公钥
+func JWKSHandler(ks *KeySet) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		_ = json.NewEncoder(w).Encode(ks.JWKS())
+	})
+}
+
+type JWKSOptions struct {
+	Client             *http.Client  // 请求 JWKS 的客户端，默认超时10秒
+	RefreshInterval    time.Duration // 缓存有效期，默认10分钟
+	MinRefreshInterval time.Duration // 遇到未知 kid 时两次刷新的最小间隔，默认1分钟
+}
+
+type JWKSOption func(o *JWKSOptions)
+
+func WithHTTPClient(client *http.Client) JWKSOption {
+	return func(o *JWKSOptions) {
+		o.Client = client
+	}
+}
+
+func WithRefreshInterval(d time.Duration) JWKSOption {
+	return func(o *JWKSOptions) {
+		o.RefreshInterval = d
+	}
+}
+
+func WithMinRefreshInterval(d time.Duration) JWKSOption {
+	return func(o *JWKSOptions) {
+		o.MinRefreshInterval = d
+	}
+}
+
+type remoteKey struct {
+	kty string
+	alg string
+	key interface{}
+}
+
+// RemoteKeySet 从远程 JWKS 地址获取并缓存公钥，用于验签
+type RemoteKeySet struct {
+	url   string
+	opts  JWKSOptions
+	group singleflight.Group
+
+	mu          sync.Mutex
+	keys        map[string]remoteKey
+	fetchedAt   time.Time // 最近一次成功获取的时间
+	attemptedAt time.Time // 最近一次尝试获取的时间
+}
+
+func NewRemoteKeySet(url string, opts ...JWKSOption) *RemoteKeySet {
+	o := JWKSOptions{
+		Client:             &http.Client{Timeout: 10 * time.Second},
+		RefreshInterval:    10 * time.Minute,
+		MinRefreshInterval: time.Minute,
+	}
+	for _, f := range opts {
+		f(&o)
+	}
+
+	return &RemoteKeySet{
+		url:  url,
+		opts: o,
+	}
+}
+
+// Keyfunc 按token头中的 kid 返回公钥，缓存过期或 kid 未知时重新获取 JWKS
+func (r *RemoteKeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrKidRequired
+	}
+
+	key, err := r.lookup(context.Background(), kid)
+	if err != nil {
+		return nil, err
+	}
+	if !key.allows(token.Method.Alg()) {
+		return nil, ErrUnsupportedMethod
+	}
+
+	return key.key, nil
+}
+
+// allows JWK 声明了 alg 时要求一致，否则按 kty 校验算法族，防止算法混淆
+func (k remoteKey) allows(alg string) bool {
+	if k.alg != "" {
+		return k.alg == alg
+	}
+
+	switch k.kty {
+	case "RSA":
+		return strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS")
+	case "EC":
+		return strings.HasPrefix(alg, "ES")
+	case "OKP":
+		return alg == "EdDSA"
+	default:
+		return false
+	}
+}
+
+// Refresh 立即重新获取 JWKS，并发调用只会发出一次请求
+func (r *RemoteKeySet) Refresh(ctx context.Context) error {
+	_, err, _ := r.group.Do(r.url, func() (interface{}, error) {
+		return nil, r.refresh(ctx)
+	})
+	return err
+}
+
+func (r *RemoteKeySet) lookup(ctx context.Context, kid string) (remoteKey, error) {
+	r.mu.Lock()
+	key, ok := r.keys[kid]
+	expired := time.Since(r.fetchedAt) >= r.opts.RefreshInterval
+	throttled := time.Since(r.attemptedAt) < r.opts.MinRefreshInterval
+	r.mu.Unlock()
+
+	// 网络请求不持有锁，避免慢速的 JWKS 地址阻塞其他 kid 的查找
+	if (expired || !ok) && !throttled {
+		if err := r.Refresh(ctx); err != nil {
+			// 获取失败时继续使用缓存
+			if !ok {
+				return remoteKey{}, err
+			}
+			return key, nil
+		}
+
+		r.mu.Lock()
+		key, ok = r.keys[kid]
+		r.mu.Unlock()
+	}
+
+	if !ok {
+		return remoteKey{}, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+func (r *RemoteKeySet) refresh(ctx context.Context) error {
+	r.mu.Lock()
+	r.attemptedAt = time.Now()
+	r.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	var set JWKS
+	if err = json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]remoteKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		pub, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = remoteKey{kty: jwk.Kty, alg: jwk.Alg, key: pub}
+	}
+
+	r.mu.Lock()
+	r.keys = keys
+	r.fetchedAt = time.Now()
+	r.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,182 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newRSAKey(t *testing.T, kid string) Key {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Key{Kid: kid, Method: jwt.SigningMethodRS256, SignKey: priv, VerifyKey: &priv.PublicKey}
+}
+
+func newRemoteTestServer(t *testing.T, handler http.Handler) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRemoteKeySetRotation(t *testing.T) {
+	ks := NewKeySet()
+	ks.Add(newRSAKey(t, "k1"))
+	if err := ks.SetActive("k1"); err != nil {
+		t.Fatal(err)
+	}
+
+	var fetches int32
+	srv := newRemoteTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		JWKSHandler(ks).ServeHTTP(w, r)
+	}))
+
+	issuer := NewAuthToken(nil, WithKeySet(ks))
+	remote := NewRemoteKeySet(srv.URL, WithMinRefreshInterval(0))
+	verifier := NewAuthToken(nil, WithKeyFunc(remote.Keyfunc))
+
+	tokenString, err := issuer.CreateToken(Claims{UserId: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = verifier.ParseToken(tokenString); err != nil {
+		t.Fatalf("parse with k1: %v", err)
+	}
+
+	// 轮换到新的密钥，未知 kid 触发重新获取
+	ks.Add(newRSAKey(t, "k2"))
+	if err = ks.SetActive("k2"); err != nil {
+		t.Fatal(err)
+	}
+	tokenString, err = issuer.CreateToken(Claims{UserId: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := verifier.ParseToken(tokenString)
+	if err != nil {
+		t.Fatalf("parse with k2: %v", err)
+	}
+	if claims.UserId != 2 {
+		t.Fatalf("UserId = %d, want 2", claims.UserId)
+	}
+	if n := atomic.LoadInt32(&fetches); n != 2 {
+		t.Fatalf("fetches = %d, want 2", n)
+	}
+}
+
+func TestRemoteKeySetRejectsAlgorithmConfusion(t *testing.T) {
+	key := newRSAKey(t, "k1")
+	jwk, err := NewJWK("k1", "", key.VerifyKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newRemoteTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(JWKS{Keys: []JWK{jwk}})
+	}))
+
+	verifier := NewAuthToken(nil, WithKeyFunc(NewRemoteKeySet(srv.URL).Keyfunc))
+
+	// JWK 未声明 alg 时按 kty 允许 RSA 算法
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, &Claims{UserId: 1})
+	token.Header["kid"] = "k1"
+	tokenString, err := token.SignedString(key.SignKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = verifier.ParseToken(tokenString); err != nil {
+		t.Fatalf("parse RS256: %v", err)
+	}
+
+	token = jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{UserId: 1})
+	token.Header["kid"] = "k1"
+	tokenString, err = token.SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = verifier.ParseToken(tokenString); err == nil {
+		t.Fatal("HS256 token accepted with RSA JWK")
+	}
+}
+
+func TestRemoteKeySetSlowEndpointUsesCache(t *testing.T) {
+	ks := NewKeySet()
+	ks.Add(newRSAKey(t, "k1"))
+	if err := ks.SetActive("k1"); err != nil {
+		t.Fatal(err)
+	}
+
+	var slow int32
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	srv := newRemoteTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&slow) == 1 {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		JWKSHandler(ks).ServeHTTP(w, r)
+	}))
+
+	remote := NewRemoteKeySet(srv.URL,
+		WithHTTPClient(&http.Client{Timeout: 100 * time.Millisecond}),
+		WithRefreshInterval(time.Millisecond),
+		WithMinRefreshInterval(0),
+	)
+	issuer := NewAuthToken(nil, WithKeySet(ks))
+	verifier := NewAuthToken(nil, WithKeyFunc(remote.Keyfunc))
+
+	tokenString, err := issuer.CreateToken(Claims{UserId: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = verifier.ParseToken(tokenString); err != nil {
+		t.Fatal(err)
+	}
+
+	atomic.StoreInt32(&slow, 1)
+	time.Sleep(2 * time.Millisecond)
+
+	start := time.Now()
+	if _, err = verifier.ParseToken(tokenString); err != nil {
+		t.Fatalf("parse with cached key: %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("parse took %v, want bounded by client timeout", d)
+	}
+}
+
+func TestRemoteKeyAllows(t *testing.T) {
+	tests := []struct {
+		key  remoteKey
+		alg  string
+		want bool
+	}{
+		{remoteKey{kty: "RSA", alg: "RS256"}, "RS256", true},
+		{remoteKey{kty: "RSA", alg: "RS256"}, "PS256", false},
+		{remoteKey{kty: "RSA"}, "PS384", true},
+		{remoteKey{kty: "RSA"}, "HS256", false},
+		{remoteKey{kty: "EC"}, "ES256", true},
+		{remoteKey{kty: "EC"}, "RS256", false},
+		{remoteKey{kty: "OKP"}, "EdDSA", true},
+		{remoteKey{kty: "OKP"}, "none", false},
+		{remoteKey{kty: "oct"}, "HS256", false},
+	}
+	for _, tt := range tests {
+		if got := tt.key.allows(tt.alg); got != tt.want {
+			t.Errorf("%+v allows %s = %v, want %v", tt.key, tt.alg, got, tt.want)
+		}
+	}
+}
@@ -1,67 +1,311 @@
 package token
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/golang-jwt/jwt/v5/request"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/xiaWave/go-common-module/error_codes"
 )
 
 var (
 	ErrTokenInvalid = errors.New("couldn't handle this token")
 )
 
-type Claims struct {
-	jwt.RegisteredClaims
-	UserId int64  // 应用内部用户id
-	SignId string // 单点用户id
+// AuthToken 创建后不可修改，可被多个 goroutine 并发使用
+type AuthToken struct {
+	signingKey []byte // 密钥
+	opts       Options
 }
 
-type AuthToken struct {
-	SigningKey []byte                 // 密钥
-	Header     map[string]interface{} // 头信息
-	Raw        string                 // 加密token
+// ParseResult token解析结果
+type ParseResult[C any] struct {
+	Claims *C                     // 声明
+	Header map[string]interface{} // 头信息
+	Raw    string                 // 原始token，加密时为 JWE
+	Kid    string                 // 签名密钥标识
 }
 
-func NewAuthToken(secret []byte) *AuthToken {
-	return &AuthToken{
-		SigningKey: secret,
+// NewAuthToken 默认使用 HS256 算法，secret 为对称密钥，使用非对称算法时可传 nil 并通过 Option 设置密钥
+func NewAuthToken(secret []byte, opts ...Option) *AuthToken {
+	p := &AuthToken{
+		signingKey: append([]byte(nil), secret...),
+		opts: Options{
+			SigningMethod: jwt.SigningMethodHS256,
+			AccessTTL:     defaultAccessTTL,
+			RefreshTTL:    defaultRefreshTTL,
+			Clock:         time.Now,
+		},
+	}
+	for _, o := range opts {
+		o(&p.opts)
+	}
+
+	// 复制切片，避免调用方在创建后修改配置
+	p.opts.ValidMethods = append([]string(nil), p.opts.ValidMethods...)
+	p.opts.Audience = append([]string(nil), p.opts.Audience...)
+	p.opts.RequiredClaims = append([]string(nil), p.opts.RequiredClaims...)
+	p.opts.Extractors = append([]Extractor(nil), p.opts.Extractors...)
+
+	if len(p.opts.Extractors) == 0 {
+		p.opts.Extractors = defaultExtractors()
+	}
+
+	// 使用密钥集合或自定义密钥查找时，由其按 kid 校验算法
+	if len(p.opts.ValidMethods) == 0 && p.opts.KeySet == nil && p.opts.KeyFunc == nil {
+		p.opts.ValidMethods = []string{p.opts.SigningMethod.Alg()}
 	}
+
+	return p
 }
 
-// CreateToken 创建一个token
+// CreateToken 创建一个token，未设置 jti 与签发时间时自动生成
 func (p *AuthToken) CreateToken(claims Claims) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(p.SigningKey)
+	return p.createToken(&claims)
+}
+
+// createToken 签名任意 Claims，claims 需为指针以便填充 jwt.RegisteredClaims
+func (p *AuthToken) createToken(claims jwt.Claims) (string, error) {
+	if rc := registeredClaims(claims); rc != nil {
+		if rc.ID == "" {
+			jti, err := randomString(16)
+			if err != nil {
+				return "", err
+			}
+			rc.ID = jti
+		}
+		if rc.IssuedAt == nil {
+			now := p.opts.Clock()
+			rc.IssuedAt = jwt.NewNumericDate(now)
+			if c, ok := claims.(*Claims); ok {
+				c.IssuedAtMicro = now.UnixMicro()
+			}
+		}
+		if rc.Issuer == "" {
+			rc.Issuer = p.opts.Issuer
+		}
+		if len(rc.Audience) == 0 && len(p.opts.Audience) > 0 {
+			rc.Audience = p.opts.Audience
+		}
+	}
+
+	signed, err := p.sign(claims)
+	if err != nil || p.opts.Encryption == nil {
+		return signed, err
+	}
+	return p.opts.Encryption.encrypt(signed)
+}
+
+func (p *AuthToken) sign(claims jwt.Claims) (string, error) {
+	if p.opts.KeySet == nil {
+		token := jwt.NewWithClaims(p.opts.SigningMethod, claims)
+		return token.SignedString(p.signKey())
+	}
+
+	key, err := p.opts.KeySet.Active()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.Kid
+	return token.SignedString(key.SignKey)
 }
 
-// ParseFromRequest 解析token
+// ParseFromRequest 按配置的来源顺序提取并解析token
 func (p *AuthToken) ParseFromRequest(req *http.Request) (*Claims, error) {
-	tokenString, err := request.OAuth2Extractor.ExtractToken(req)
+	for _, e := range p.opts.Extractors {
+		if tokenString, ok := e.FromRequest(req); ok {
+			return p.ParseTokenContext(req.Context(), tokenString)
+		}
+	}
+
+	return nil, request.ErrNoTokenInRequest
+}
+
+// ParseFromContext 按配置的来源顺序从gRPC metadata 中提取并解析token
+func (p *AuthToken) ParseFromContext(ctx context.Context) (*Claims, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, e := range p.opts.Extractors {
+		if tokenString, ok := e.FromMetadata(md); ok {
+			return p.ParseTokenContext(ctx, tokenString)
+		}
+	}
+
+	return nil, request.ErrNoTokenInRequest
+}
+
+func (p *AuthToken) ParseToken(tokenString string) (*Claims, error) {
+	return p.ParseTokenContext(context.Background(), tokenString)
+}
+
+// ParseTokenContext 解析token，设置了 Revoker 时检查token是否已被撤销，设置了 SessionRegistry 时检查设备会话是否有效
+func (p *AuthToken) ParseTokenContext(ctx context.Context, tokenString string) (*Claims, error) {
+	result, err := p.Parse(ctx, tokenString)
 	if err != nil {
 		return nil, err
 	}
+	return result.Claims, nil
+}
 
-	return p.ParseToken(tokenString)
+// Parse 解析token，返回声明、头信息与原始token
+func (p *AuthToken) Parse(ctx context.Context, tokenString string) (*ParseResult[Claims], error) {
+	return ParseInto[Claims](ctx, p, tokenString)
 }
 
-func (p *AuthToken) ParseToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (i interface{}, e error) {
-		return p.SigningKey, nil
-	})
+// parseToken 解析token到任意 Claims 并检查撤销状态，设置了加密时先解密
+func (p *AuthToken) parseToken(ctx context.Context, tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	if p.opts.Encryption != nil {
+		signed, err := p.opts.Encryption.decrypt(tokenString)
+		if err != nil {
+			return nil, err
+		}
+		tokenString = signed
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, p.keyFunc, p.parserOptions()...)
 
 	if err != nil {
 		return nil, err
 	}
 
-	p.Header = token.Header
-	p.Raw = token.Raw
+	if !token.Valid {
+		return nil, ErrTokenInvalid
+	}
+
+	if err = p.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	if p.opts.Revoker != nil {
+		revoked, err := p.opts.Revoker.IsRevoked(ctx, claims)
+		if err != nil {
+			return nil, error_codes.NewWithError(error_codes.CacheErr, "", err)
+		}
+		if revoked {
+			return nil, error_codes.TokenInvalidError
+		}
+	}
+
+	if p.opts.Sessions != nil {
+		active, err := p.opts.Sessions.IsActive(ctx, claims)
+		if err != nil {
+			return nil, error_codes.NewWithError(error_codes.CacheErr, "", err)
+		}
+		if !active {
+			return nil, error_codes.TokenInvalidError
+		}
+	}
+
+	return token, nil
+}
+
+func (p *AuthToken) signKey() interface{} {
+	if p.opts.SignKey != nil {
+		return p.opts.SignKey
+	}
+	return p.signingKey
+}
+
+func (p *AuthToken) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch {
+	case p.opts.KeyFunc != nil:
+		return p.opts.KeyFunc(token)
+	case p.opts.KeySet != nil:
+		return p.opts.KeySet.Keyfunc(token)
+	default:
+		return p.verifyKey(), nil
+	}
+}
+
+func (p *AuthToken) parserOptions() []jwt.ParserOption {
+	opts := []jwt.ParserOption{
+		jwt.WithLeeway(p.opts.Leeway),
+		jwt.WithTimeFunc(p.opts.Clock),
+	}
+	if len(p.opts.ValidMethods) > 0 {
+		opts = append(opts, jwt.WithValidMethods(p.opts.ValidMethods))
+	}
+	if p.opts.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(p.opts.Issuer))
+	}
+	return opts
+}
+
+// validateClaims 校验受众与必须存在的声明，未配置 ClientAudience 时拒绝客户端凭证模式签发的token
+func (p *AuthToken) validateClaims(claims jwt.Claims) error {
+	aud, err := claims.GetAudience()
+	if err != nil {
+		return err
+	}
+	if len(p.opts.Audience) > 0 && !containsAny(aud, p.opts.Audience) {
+		return jwt.ErrTokenInvalidAudience
+	}
+	if containsAny(aud, []string{ClientAudience}) && !containsAny(p.opts.Audience, []string{ClientAudience}) {
+		return jwt.ErrTokenInvalidAudience
+	}
+
+	for _, name := range p.opts.RequiredClaims {
+		present, err := hasClaim(claims, name)
+		if err != nil {
+			return err
+		}
+		if !present {
+			return fmt.Errorf("%w: %s", jwt.ErrTokenRequiredClaimMissing, name)
+		}
+	}
 
-	claims, ok := token.Claims.(*Claims)
-	if ok && token.Valid {
-		return claims, nil
+	return nil
+}
+
+func hasClaim(claims jwt.Claims, name string) (bool, error) {
+	switch name {
+	case "exp":
+		v, err := claims.GetExpirationTime()
+		return v != nil, err
+	case "iat":
+		v, err := claims.GetIssuedAt()
+		return v != nil, err
+	case "nbf":
+		v, err := claims.GetNotBefore()
+		return v != nil, err
+	case "iss":
+		v, err := claims.GetIssuer()
+		return v != "", err
+	case "aud":
+		v, err := claims.GetAudience()
+		return len(v) > 0, err
+	case "sub":
+		v, err := claims.GetSubject()
+		return v != "", err
+	case "jti":
+		rc := registeredClaims(claims)
+		return rc != nil && rc.ID != "", nil
+	default:
+		return false, fmt.Errorf("unsupported required claim %q", name)
 	}
+}
 
-	return nil, ErrTokenInvalid
+func containsAny(values []string, targets []string) bool {
+	for _, v := range values {
+		for _, t := range targets {
+			if v == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *AuthToken) verifyKey() interface{} {
+	if p.opts.VerifyKey != nil {
+		return p.opts.VerifyKey
+	}
+	return p.signingKey
 }
@@ -0,0 +1,430 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/xiaWave/go-common-module/cache"
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+// memCache 测试用的内存 cache.Cache
+type memCache struct {
+	mu    sync.Mutex
+	items map[string]memItem
+}
+
+type memItem struct {
+	value    string
+	expireAt time.Time
+}
+
+func newMemCache() *memCache {
+	return &memCache{items: make(map[string]memItem)}
+}
+
+func (c *memCache) get(key string) (string, bool) {
+	item, ok := c.items[key]
+	if !ok || (!item.expireAt.IsZero() && time.Now().After(item.expireAt)) {
+		delete(c.items, key)
+		return "", false
+	}
+	return item.value, true
+}
+
+func (c *memCache) set(key string, val interface{}, expiration time.Duration) {
+	item := memItem{value: fmt.Sprint(val)}
+	if expiration > 0 {
+		item.expireAt = time.Now().Add(expiration)
+	}
+	c.items[key] = item
+}
+
+func (c *memCache) Set(ctx context.Context, key string, val interface{}, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(key, val, expiration)
+	return nil
+}
+
+func (c *memCache) SetNX(ctx context.Context, key string, val interface{}, expiration time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.get(key); ok {
+		return false, nil
+	}
+	c.set(key, val, expiration)
+	return true, nil
+}
+
+func (c *memCache) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var n int64
+	if v, ok := c.get(key); ok {
+		if _, err := fmt.Sscan(v, &n); err != nil {
+			return 0, err
+		}
+		c.items[key] = memItem{value: fmt.Sprint(n + 1), expireAt: c.items[key].expireAt}
+		return n + 1, nil
+	}
+	c.set(key, 1, expiration)
+	return 1, nil
+}
+
+func (c *memCache) Decr(ctx context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var n int64
+	if v, ok := c.get(key); ok {
+		if _, err := fmt.Sscan(v, &n); err != nil {
+			return 0, err
+		}
+	}
+	c.items[key] = memItem{value: fmt.Sprint(n - 1), expireAt: c.items[key].expireAt}
+	return n - 1, nil
+}
+
+func (c *memCache) CompareAndDelete(ctx context.Context, key string, val string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok := c.get(key); !ok || v != val {
+		return false, nil
+	}
+	delete(c.items, key)
+	return true, nil
+}
+
+func (c *memCache) Get(ctx context.Context, key string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, _ := c.get(key)
+	return v, nil
+}
+
+func (c *memCache) GetDel(ctx context.Context, key string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, _ := c.get(key)
+	delete(c.items, key)
+	return v, nil
+}
+
+func (c *memCache) Scan(ctx context.Context, key string, val interface{}) error {
+	return errors.New("not implemented")
+}
+
+func (c *memCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.items, key)
+	}
+	return nil
+}
+
+func (c *memCache) Options() cache.Options          { return cache.Options{} }
+func (c *memCache) Ping(ctx context.Context) error  { return nil }
+func (c *memCache) Close(ctx context.Context) error { return nil }
+
+func newClaims(userId int64) Claims {
+	c := Claims{UserId: userId}
+	c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Hour))
+	return c
+}
+
+// parallel 并发执行 n 次 fn，返回第一个错误
+func parallel(n int, fn func(i int) error) error {
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := fn(i); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+func TestConcurrentCreateAndParse(t *testing.T) {
+	auth := NewAuthToken([]byte("secret"), WithIssuer("test"), WithAudience("api"))
+
+	err := parallel(64, func(i int) error {
+		for j := 0; j < 20; j++ {
+			userId := int64(i*100 + j)
+			tokenString, err := auth.CreateToken(newClaims(userId))
+			if err != nil {
+				return err
+			}
+			result, err := auth.Parse(context.Background(), tokenString)
+			if err != nil {
+				return err
+			}
+			if result.Claims.UserId != userId {
+				return fmt.Errorf("UserId = %d, want %d", result.Claims.UserId, userId)
+			}
+			if result.Claims.ID == "" || result.Claims.Issuer != "test" {
+				return fmt.Errorf("registered claims not filled: %+v", result.Claims.RegisteredClaims)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConcurrentKeyRotation(t *testing.T) {
+	ks := NewKeySet()
+	for _, kid := range []string{"k1", "k2"} {
+		ks.Add(Key{Kid: kid, Method: jwt.SigningMethodHS256, SignKey: []byte(kid), VerifyKey: []byte(kid)})
+	}
+	if err := ks.SetActive("k1"); err != nil {
+		t.Fatal(err)
+	}
+	auth := NewAuthToken(nil, WithKeySet(ks))
+
+	err := parallel(32, func(i int) error {
+		for j := 0; j < 20; j++ {
+			if i%8 == 0 {
+				if err := ks.SetActive([]string{"k1", "k2"}[j%2]); err != nil {
+					return err
+				}
+			}
+			tokenString, err := auth.CreateToken(newClaims(int64(i)))
+			if err != nil {
+				return err
+			}
+			if _, err = auth.ParseToken(tokenString); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConcurrentRevoker(t *testing.T) {
+	// 本地缓存容量较小，同时覆盖 LRU 淘汰
+	revoker := NewRevoker(newMemCache(), WithLocalSize(8))
+	auth := NewAuthToken([]byte("secret"), WithRevoker(revoker))
+	ctx := context.Background()
+
+	err := parallel(32, func(i int) error {
+		for j := 0; j < 10; j++ {
+			tokenString, err := auth.CreateToken(newClaims(int64(i)))
+			if err != nil {
+				return err
+			}
+			claims, err := auth.ParseToken(tokenString)
+			if err != nil {
+				return err
+			}
+
+			if j%2 == 1 {
+				continue
+			}
+			if err = revoker.Revoke(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+				return err
+			}
+			if _, err = auth.ParseToken(tokenString); !errors.Is(err, error_codes.TokenInvalidError) {
+				return fmt.Errorf("revoked token: err = %v, want TokenInvalid", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConcurrentRevokeUser(t *testing.T) {
+	revoker := NewRevoker(newMemCache())
+	auth := NewAuthToken([]byte("secret"), WithRevoker(revoker))
+	ctx := context.Background()
+
+	tokens := make([]string, 16)
+	for i := range tokens {
+		tokenString, err := auth.CreateToken(newClaims(int64(i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tokens[i] = tokenString
+	}
+
+	err := parallel(len(tokens), func(i int) error {
+		if err := revoker.RevokeUser(ctx, int64(i), time.Now()); err != nil {
+			return err
+		}
+		if _, err := auth.ParseToken(tokens[i]); !errors.Is(err, error_codes.TokenInvalidError) {
+			return fmt.Errorf("user %d: err = %v, want TokenInvalid", i, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRevokeUserSameSecond(t *testing.T) {
+	base := time.Now().Truncate(time.Second)
+	now := base
+	clock := func() time.Time { return now }
+	revoker := NewRevoker(newMemCache())
+	auth := NewAuthToken([]byte("secret"), WithRevoker(revoker), WithClock(clock))
+	ctx := context.Background()
+
+	now = base.Add(100 * time.Millisecond)
+	before, err := auth.CreateToken(newClaims(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = revoker.RevokeUser(ctx, 7, base.Add(500*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 与撤销时间点同一秒内、在其之后签发的token仍然有效
+	now = base.Add(900 * time.Millisecond)
+	after, err := auth.CreateToken(newClaims(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = auth.ParseToken(before); !errors.Is(err, error_codes.TokenInvalidError) {
+		t.Fatalf("token before revocation: err = %v, want TokenInvalid", err)
+	}
+	if _, err = auth.ParseToken(after); err != nil {
+		t.Fatalf("token after revocation: %v", err)
+	}
+}
+
+func TestRevokeUserThenCreate(t *testing.T) {
+	revoker := NewRevoker(newMemCache())
+	auth := NewAuthToken([]byte("secret"), WithRevoker(revoker))
+
+	if err := revoker.RevokeUser(context.Background(), 7, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	tokenString, err := auth.CreateToken(newClaims(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = auth.ParseToken(tokenString); err != nil {
+		t.Fatalf("token issued after revocation: %v", err)
+	}
+}
+
+func TestRefreshAfterRevoke(t *testing.T) {
+	c := newMemCache()
+	revoker := NewRevoker(c)
+	auth := NewAuthToken([]byte("secret"), WithCache(c), WithRevoker(revoker))
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		claims Claims
+		revoke func() error
+	}{
+		{"user", Claims{UserId: 1}, func() error { return revoker.RevokeUser(ctx, 1, time.Now()) }},
+		{"sign", Claims{UserId: 2, SignId: "s-2"}, func() error { return revoker.RevokeSign(ctx, "s-2", time.Now()) }},
+	}
+	for _, tt := range tests {
+		pair, err := auth.CreateTokenPair(ctx, tt.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = tt.revoke(); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = auth.RefreshToken(ctx, pair.RefreshToken); !errors.Is(err, error_codes.TokenInvalidError) {
+			t.Fatalf("%s: refresh after revoke: err = %v, want TokenInvalid", tt.name, err)
+		}
+
+		// 撤销后重新登录的token对可以正常续期
+		pair, err = auth.CreateTokenPair(ctx, tt.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pair, err = auth.RefreshToken(ctx, pair.RefreshToken); err != nil {
+			t.Fatalf("%s: refresh after re-login: %v", tt.name, err)
+		}
+		if _, err = auth.ParseToken(pair.AccessToken); err != nil {
+			t.Fatalf("%s: parse refreshed token: %v", tt.name, err)
+		}
+	}
+}
+
+func TestConcurrentTokenPairs(t *testing.T) {
+	auth := NewAuthToken([]byte("secret"), WithCache(newMemCache()))
+	ctx := context.Background()
+
+	err := parallel(32, func(i int) error {
+		pair, err := auth.CreateTokenPair(ctx, Claims{UserId: int64(i)})
+		if err != nil {
+			return err
+		}
+		for j := 0; j < 5; j++ {
+			if pair, err = auth.RefreshToken(ctx, pair.RefreshToken); err != nil {
+				return err
+			}
+			claims, err := auth.ParseToken(pair.AccessToken)
+			if err != nil {
+				return err
+			}
+			if claims.UserId != int64(i) {
+				return fmt.Errorf("UserId = %d, want %d", claims.UserId, i)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConcurrentRefreshReuse(t *testing.T) {
+	auth := NewAuthToken([]byte("secret"), WithCache(newMemCache()))
+	ctx := context.Background()
+
+	pair, err := auth.CreateTokenPair(ctx, Claims{UserId: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 同一个 refresh token 并发使用，只有一个请求能成功
+	var mu sync.Mutex
+	var succeeded []*TokenPair
+	_ = parallel(16, func(i int) error {
+		next, err := auth.RefreshToken(ctx, pair.RefreshToken)
+		if err != nil {
+			return nil
+		}
+		mu.Lock()
+		succeeded = append(succeeded, next)
+		mu.Unlock()
+		return nil
+	})
+	if len(succeeded) != 1 {
+		t.Fatalf("%d refreshes succeeded, want 1", len(succeeded))
+	}
+
+	// 重放触发整个家族撤销，成功换出的 refresh token 也随之失效
+	if _, err = auth.RefreshToken(ctx, succeeded[0].RefreshToken); !errors.Is(err, error_codes.TokenInvalidError) {
+		t.Fatalf("refresh after reuse: err = %v, want TokenInvalid", err)
+	}
+}
@@ -0,0 +1,54 @@
+package token
+
+import (
+	"crypto"
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrUnsupportedMethod = errors.New("unsupported signing method")
+	ErrKeyRequired       = errors.New("private or public key is required")
+)
+
+// LoadPEMKeys 按签名算法解析PEM格式的私钥与公钥，publicPEM 为空时由私钥推导公钥，
+// privatePEM 为空时只返回公钥，用于仅验签的场景
+func LoadPEMKeys(method jwt.SigningMethod, privatePEM, publicPEM []byte) (signKey, verifyKey interface{}, err error) {
+	if len(privatePEM) == 0 && len(publicPEM) == 0 {
+		return nil, nil, ErrKeyRequired
+	}
+
+	var parsePrivate func([]byte) (interface{}, error)
+	var parsePublic func([]byte) (interface{}, error)
+
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		parsePrivate = func(b []byte) (interface{}, error) { return jwt.ParseRSAPrivateKeyFromPEM(b) }
+		parsePublic = func(b []byte) (interface{}, error) { return jwt.ParseRSAPublicKeyFromPEM(b) }
+	case *jwt.SigningMethodECDSA:
+		parsePrivate = func(b []byte) (interface{}, error) { return jwt.ParseECPrivateKeyFromPEM(b) }
+		parsePublic = func(b []byte) (interface{}, error) { return jwt.ParseECPublicKeyFromPEM(b) }
+	case *jwt.SigningMethodEd25519:
+		parsePrivate = func(b []byte) (interface{}, error) { return jwt.ParseEdPrivateKeyFromPEM(b) }
+		parsePublic = func(b []byte) (interface{}, error) { return jwt.ParseEdPublicKeyFromPEM(b) }
+	default:
+		return nil, nil, ErrUnsupportedMethod
+	}
+
+	if len(privatePEM) > 0 {
+		if signKey, err = parsePrivate(privatePEM); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(publicPEM) > 0 {
+		if verifyKey, err = parsePublic(publicPEM); err != nil {
+			return nil, nil, err
+		}
+	} else if signer, ok := signKey.(crypto.Signer); ok {
+		verifyKey = signer.Public()
+	}
+
+	return signKey, verifyKey, nil
+}
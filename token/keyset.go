@@ -0,0 +1,125 @@
+package token
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoActiveKey = errors.New("no active signing key")
+	ErrKeyNotFound = errors.New("signing key not found")
+	ErrKidRequired = errors.New("token kid header is required")
+)
+
+// Key 以 kid 标识的密钥
+type Key struct {
+	Kid       string            // 密钥标识，写入token头的 kid
+	Method    jwt.SigningMethod // 签名算法
+	SignKey   interface{}       // 签名密钥，非对称算法为私钥，仅验签的密钥可为空
+	VerifyKey interface{}       // 验签密钥，非对称算法为公钥
+}
+
+// KeySet 支持密钥轮换的密钥集合，只有一个密钥用于签名，所有密钥都可用于验签
+type KeySet struct {
+	mu        sync.RWMutex
+	keys      map[string]*Key
+	activeKid string
+}
+
+func NewKeySet() *KeySet {
+	return &KeySet{
+		keys: make(map[string]*Key),
+	}
+}
+
+// Add 添加密钥，已存在相同 kid 时覆盖
+func (s *KeySet) Add(key Key) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key.Kid] = &key
+}
+
+// Remove 删除密钥，删除后使用该密钥签名的token将无法通过验签
+func (s *KeySet) Remove(kid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, kid)
+	if s.activeKid == kid {
+		s.activeKid = ""
+	}
+}
+
+// SetActive 设置用于签名的密钥
+func (s *KeySet) SetActive(kid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[kid]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	if key.SignKey == nil {
+		return ErrKeyRequired
+	}
+
+	s.activeKid = kid
+	return nil
+}
+
+// Active 返回用于签名的密钥
+func (s *KeySet) Active() (*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[s.activeKid]
+	if !ok {
+		return nil, ErrNoActiveKey
+	}
+	return key, nil
+}
+
+// Lookup 按 kid 查找密钥
+func (s *KeySet) Lookup(kid string) (*Key, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// Keyfunc 按token头中的 kid 返回验签密钥，并校验算法与密钥一致
+func (s *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrKidRequired
+	}
+
+	key, ok := s.Lookup(kid)
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrUnsupportedMethod
+	}
+
+	return key.VerifyKey, nil
+}
+
+// JWKS 返回所有非对称密钥的公钥，对称密钥不会被公开
+func (s *KeySet) JWKS() JWKS {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	set := JWKS{Keys: make([]JWK, 0, len(s.keys))}
+	for _, key := range s.keys {
+		jwk, err := NewJWK(key.Kid, key.Method.Alg(), key.VerifyKey)
+		if err != nil {
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
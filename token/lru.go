@@ -0,0 +1,73 @@
+package token
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lru 带过期时间的本地LRU缓存
+type lru struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key      string
+	value    interface{}
+	expireAt time.Time
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (c *lru) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := e.Value.(*lruEntry)
+	if time.Now().After(entry.expireAt) {
+		c.ll.Remove(e)
+		delete(c.items, key)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(e)
+	return entry.value, true
+}
+
+func (c *lru) Set(key string, value interface{}, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		c.ll.MoveToFront(e)
+		entry := e.Value.(*lruEntry)
+		entry.value = value
+		entry.expireAt = time.Now().Add(ttl)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&lruEntry{key: key, value: value, expireAt: time.Now().Add(ttl)})
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
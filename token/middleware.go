@@ -0,0 +1,96 @@
+package token
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+type MiddlewareOptions struct {
+	SkipPaths   []string                   // 跳过认证的HTTP路径，以 * 结尾时按前缀匹配
+	SkipMethods []string                   // 跳过认证的gRPC方法全名，以 * 结尾时按前缀匹配
+	Skipper     func(r *http.Request) bool // 自定义HTTP跳过规则
+}
+
+type MiddlewareOption func(o *MiddlewareOptions)
+
+func WithSkipPaths(paths ...string) MiddlewareOption {
+	return func(o *MiddlewareOptions) {
+		o.SkipPaths = append(o.SkipPaths, paths...)
+	}
+}
+
+func WithSkipMethods(methods ...string) MiddlewareOption {
+	return func(o *MiddlewareOptions) {
+		o.SkipMethods = append(o.SkipMethods, methods...)
+	}
+}
+
+func WithSkipper(fn func(r *http.Request) bool) MiddlewareOption {
+	return func(o *MiddlewareOptions) {
+		o.Skipper = fn
+	}
+}
+
+func newMiddlewareOptions(opts ...MiddlewareOption) MiddlewareOptions {
+	o := MiddlewareOptions{}
+	for _, f := range opts {
+		f(&o)
+	}
+	return o
+}
+
+// HTTPMiddleware 认证请求中的token，并将 Claims 写入请求 context
+func (p *AuthToken) HTTPMiddleware(opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	o := newMiddlewareOptions(opts...)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if matchAny(o.SkipPaths, r.URL.Path) || (o.Skipper != nil && o.Skipper(r)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			claims, err := p.ParseFromRequest(r)
+			if err != nil {
+				_ = error_codes.WriteJSON(w, nil, AuthError(err))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), claims)))
+		})
+	}
+}
+
+// AuthError 将解析token的错误转换为 CustomError：过期为 TokenExpired，其余为 TokenInvalid
+func AuthError(err error) error {
+	var ce *error_codes.CustomError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &ce):
+		return err
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return error_codes.NewWithError(error_codes.TokenExpired, "", err)
+	default:
+		return error_codes.NewWithError(error_codes.TokenInvalid, "", err)
+	}
+}
+
+// matchAny 精确匹配，或以 * 结尾时按前缀匹配
+func matchAny(patterns []string, s string) bool {
+	for _, pattern := range patterns {
+		if prefix := strings.TrimSuffix(pattern, "*"); prefix != pattern {
+			if strings.HasPrefix(s, prefix) {
+				return true
+			}
+		} else if pattern == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,261 @@
+package token
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ClientAudience 客户端凭证模式签发的token的受众，只有配置了该受众的 AuthToken 才会接受此类token，
+// 资源服务使用 NewAuthToken(secret, WithAudience(ClientAudience)) 校验客户端token
+const ClientAudience = "oauth2:client_credentials"
+
+// OAuth2 错误码，见 RFC 6749 5.2
+const (
+	oauth2InvalidRequest       = "invalid_request"
+	oauth2InvalidClient        = "invalid_client"
+	oauth2InvalidScope         = "invalid_scope"
+	oauth2UnsupportedGrantType = "unsupported_grant_type"
+	oauth2ServerError          = "server_error"
+)
+
+// OAuth2Client 注册的 OAuth2 客户端
+type OAuth2Client struct {
+	ID     string
+	Secret string
+	Scopes []string // 允许申请的授权范围
+}
+
+// ClientStore 根据客户端id查找客户端，找不到时返回 nil
+type ClientStore interface {
+	Client(ctx context.Context, id string) (*OAuth2Client, error)
+}
+
+// StaticClients 固定的客户端列表，key 为客户端id
+type StaticClients map[string]OAuth2Client
+
+func (s StaticClients) Client(ctx context.Context, id string) (*OAuth2Client, error) {
+	c, ok := s[id]
+	if !ok {
+		return nil, nil
+	}
+	c.ID = id
+	return &c, nil
+}
+
+// OAuth2Token 令牌端点的响应，见 RFC 6749 5.1
+type OAuth2Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+// Introspection 令牌内省结果，见 RFC 7662 2.2
+type Introspection struct {
+	Active    bool     `json:"active"`
+	Scope     string   `json:"scope,omitempty"`
+	ClientId  string   `json:"client_id,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+	Exp       int64    `json:"exp,omitempty"`
+	Iat       int64    `json:"iat,omitempty"`
+	Nbf       int64    `json:"nbf,omitempty"`
+	Sub       string   `json:"sub,omitempty"`
+	Aud       []string `json:"aud,omitempty"`
+	Iss       string   `json:"iss,omitempty"`
+	Jti       string   `json:"jti,omitempty"`
+}
+
+// oauth2Error OAuth2 错误响应
+type oauth2Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+	status      int
+}
+
+func (e *oauth2Error) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+// OAuth2Server 客户端凭证模式(client_credentials)的授权服务，签发的token以客户端id为 sub
+type OAuth2Server struct {
+	auth    *AuthToken
+	clients ClientStore
+}
+
+// NewOAuth2Server 使用 auth 的密钥与配置签发客户端token，受众固定为 ClientAudience，
+// 内省时只有客户端token才会返回 active
+func NewOAuth2Server(auth *AuthToken, clients ClientStore) *OAuth2Server {
+	o := auth.opts
+	o.Audience = []string{ClientAudience}
+	// 客户端token不绑定设备
+	o.Sessions = nil
+
+	return &OAuth2Server{
+		auth:    &AuthToken{signingKey: auth.signingKey, opts: o},
+		clients: clients,
+	}
+}
+
+// Issue 校验客户端凭证并签发token，scopes 为空时授予客户端允许的全部授权范围
+func (s *OAuth2Server) Issue(ctx context.Context, clientId, clientSecret string, scopes []string) (*OAuth2Token, error) {
+	client, err := s.authenticate(ctx, clientId, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+	for _, scope := range scopes {
+		if !containsAny([]string{scope}, client.Scopes) {
+			return nil, &oauth2Error{Code: oauth2InvalidScope, Description: scope, status: http.StatusBadRequest}
+		}
+	}
+
+	now := s.auth.opts.Clock()
+	claims := Claims{Scopes: scopes}
+	claims.Subject = client.ID
+	claims.Audience = jwt.ClaimStrings{ClientAudience}
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.IssuedAtMicro = now.UnixMicro()
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(s.auth.opts.AccessTTL))
+
+	accessToken, err := s.auth.CreateToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OAuth2Token{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.auth.opts.AccessTTL.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
+
+// Introspect 内省token，无效的token返回 active 为 false
+func (s *OAuth2Server) Introspect(ctx context.Context, tokenString string) *Introspection {
+	claims, err := s.auth.ParseTokenContext(ctx, tokenString)
+	if err != nil {
+		return &Introspection{Active: false}
+	}
+
+	result := &Introspection{
+		Active:    true,
+		Scope:     strings.Join(claims.Scopes, " "),
+		ClientId:  claims.Subject,
+		TokenType: "Bearer",
+		Sub:       claims.Subject,
+		Aud:       claims.Audience,
+		Iss:       claims.Issuer,
+		Jti:       claims.ID,
+	}
+	if claims.ExpiresAt != nil {
+		result.Exp = claims.ExpiresAt.Unix()
+	}
+	if claims.IssuedAt != nil {
+		result.Iat = claims.IssuedAt.Unix()
+	}
+	if claims.NotBefore != nil {
+		result.Nbf = claims.NotBefore.Unix()
+	}
+	return result
+}
+
+// TokenHandler 令牌端点，支持 HTTP Basic 或表单参数传递客户端凭证
+func (s *OAuth2Server) TokenHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeOAuth2Error(w, &oauth2Error{Code: oauth2InvalidRequest, status: http.StatusMethodNotAllowed})
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			writeOAuth2Error(w, &oauth2Error{Code: oauth2InvalidRequest, status: http.StatusBadRequest})
+			return
+		}
+		if r.PostForm.Get("grant_type") != "client_credentials" {
+			writeOAuth2Error(w, &oauth2Error{Code: oauth2UnsupportedGrantType, status: http.StatusBadRequest})
+			return
+		}
+
+		clientId, clientSecret := clientCredentials(r)
+		token, err := s.Issue(r.Context(), clientId, clientSecret, strings.Fields(r.PostForm.Get("scope")))
+		if err != nil {
+			writeOAuth2Error(w, err)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(token)
+	})
+}
+
+// IntrospectionHandler 内省端点，调用方需使用已注册的客户端凭证认证
+func (s *OAuth2Server) IntrospectionHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeOAuth2Error(w, &oauth2Error{Code: oauth2InvalidRequest, status: http.StatusMethodNotAllowed})
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			writeOAuth2Error(w, &oauth2Error{Code: oauth2InvalidRequest, status: http.StatusBadRequest})
+			return
+		}
+
+		clientId, clientSecret := clientCredentials(r)
+		if _, err := s.authenticate(r.Context(), clientId, clientSecret); err != nil {
+			writeOAuth2Error(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(s.Introspect(r.Context(), r.PostForm.Get("token")))
+	})
+}
+
+func (s *OAuth2Server) authenticate(ctx context.Context, clientId, clientSecret string) (*OAuth2Client, error) {
+	if clientId == "" {
+		return nil, &oauth2Error{Code: oauth2InvalidClient, status: http.StatusUnauthorized}
+	}
+
+	client, err := s.clients.Client(ctx, clientId)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil || subtle.ConstantTimeCompare([]byte(client.Secret), []byte(clientSecret)) != 1 {
+		return nil, &oauth2Error{Code: oauth2InvalidClient, status: http.StatusUnauthorized}
+	}
+	return client, nil
+}
+
+// clientCredentials 优先从 HTTP Basic 中读取客户端凭证，其次为表单参数
+func clientCredentials(r *http.Request) (string, string) {
+	if id, secret, ok := r.BasicAuth(); ok {
+		return id, secret
+	}
+	return r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+}
+
+func writeOAuth2Error(w http.ResponseWriter, err error) {
+	e, ok := err.(*oauth2Error)
+	if !ok {
+		e = &oauth2Error{Code: oauth2ServerError, status: http.StatusInternalServerError}
+	}
+	if e.status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth2"`)
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.status)
+	_ = json.NewEncoder(w).Encode(e)
+}
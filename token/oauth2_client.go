@@ -0,0 +1,167 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type TokenSourceOptions struct {
+	Client        *http.Client     // 请求令牌端点的客户端
+	Scopes        []string         // 申请的授权范围，为空时由服务端授予全部
+	RefreshBefore time.Duration    // 过期前多久重新获取token，默认1分钟
+	Clock         func() time.Time // 时钟，默认 time.Now
+}
+
+type TokenSourceOption func(o *TokenSourceOptions)
+
+func WithTokenHTTPClient(client *http.Client) TokenSourceOption {
+	return func(o *TokenSourceOptions) {
+		o.Client = client
+	}
+}
+
+func WithScopes(scopes ...string) TokenSourceOption {
+	return func(o *TokenSourceOptions) {
+		o.Scopes = scopes
+	}
+}
+
+func WithRefreshBefore(d time.Duration) TokenSourceOption {
+	return func(o *TokenSourceOptions) {
+		o.RefreshBefore = d
+	}
+}
+
+func WithTokenSourceClock(clock func() time.Time) TokenSourceOption {
+	return func(o *TokenSourceOptions) {
+		o.Clock = clock
+	}
+}
+
+// ClientCredentialsSource 使用客户端凭证获取并缓存 access token，过期前自动重新获取
+type ClientCredentialsSource struct {
+	tokenURL     string
+	clientId     string
+	clientSecret string
+	opts         TokenSourceOptions
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func NewClientCredentialsSource(tokenURL, clientId, clientSecret string, opts ...TokenSourceOption) *ClientCredentialsSource {
+	o := TokenSourceOptions{
+		Client:        http.DefaultClient,
+		RefreshBefore: time.Minute,
+		Clock:         time.Now,
+	}
+	for _, f := range opts {
+		f(&o)
+	}
+
+	return &ClientCredentialsSource{
+		tokenURL:     tokenURL,
+		clientId:     clientId,
+		clientSecret: clientSecret,
+		opts:         o,
+	}
+}
+
+// Token 返回缓存的 access token，即将过期时重新获取
+func (s *ClientCredentialsSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && s.opts.Clock().Add(s.opts.RefreshBefore).Before(s.expiresAt) {
+		return s.token, nil
+	}
+
+	token, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	s.token = token.AccessToken
+	s.expiresAt = s.opts.Clock().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return s.token, nil
+}
+
+// Invalidate 丢弃缓存的token，如服务端返回401时调用
+func (s *ClientCredentialsSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = ""
+}
+
+func (s *ClientCredentialsSource) fetch(ctx context.Context) (*OAuth2Token, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(s.opts.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.opts.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(s.clientId, s.clientSecret)
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		e := &oauth2Error{}
+		if json.NewDecoder(resp.Body).Decode(e) != nil || e.Code == "" {
+			return nil, fmt.Errorf("fetch token: unexpected status %d", resp.StatusCode)
+		}
+		return nil, fmt.Errorf("fetch token: %w", e)
+	}
+
+	token := &OAuth2Token{}
+	if err = json.NewDecoder(resp.Body).Decode(token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("fetch token: empty access token")
+	}
+	return token, nil
+}
+
+// BearerTransport 为发出的请求自动附加 access token 的 http.RoundTripper
+type BearerTransport struct {
+	Source *ClientCredentialsSource
+	Base   http.RoundTripper // 为空时使用 http.DefaultTransport
+}
+
+func (t *BearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.Source.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	// RoundTripper 不能修改原始请求
+	authorized := req.Clone(req.Context())
+	authorized.Header.Set("Authorization", "Bearer "+token)
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(authorized)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		t.Source.Invalidate()
+	}
+	return resp, err
+}
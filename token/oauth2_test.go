@@ -0,0 +1,152 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func newOAuth2TestServer(t *testing.T, auth *AuthToken) (*OAuth2Server, *httptest.Server) {
+	t.Helper()
+	srv := NewOAuth2Server(auth, StaticClients{
+		"billing": {Secret: "billing-secret", Scopes: []string{"orders:read", "orders:write"}},
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/token", srv.TokenHandler())
+	mux.Handle("/introspect", srv.IntrospectionHandler())
+	hs := httptest.NewServer(mux)
+	t.Cleanup(hs.Close)
+	return srv, hs
+}
+
+func introspect(t *testing.T, hs *httptest.Server, tokenString string) Introspection {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, hs.URL+"/introspect", strings.NewReader(url.Values{"token": {tokenString}}.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("billing", "billing-secret")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("introspect status = %d", resp.StatusCode)
+	}
+
+	var result Introspection
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestClientCredentialsTokenAudience(t *testing.T) {
+	auth := NewAuthToken([]byte("secret"))
+	srv, hs := newOAuth2TestServer(t, auth)
+
+	src := NewClientCredentialsSource(hs.URL+"/token", "billing", "billing-secret", WithScopes("orders:read"))
+	clientToken, err := src.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 用户token的 AuthToken 不接受客户端token，避免其被当作 UserId 为0的用户
+	if _, err = auth.ParseToken(clientToken); err == nil {
+		t.Fatal("user AuthToken accepted a client-credentials token")
+	}
+
+	resource := NewAuthToken([]byte("secret"), WithAudience(ClientAudience))
+	claims, err := resource.ParseToken(clientToken)
+	if err != nil {
+		t.Fatalf("resource server: %v", err)
+	}
+	if claims.Subject != "billing" || len(claims.Scopes) != 1 || claims.Scopes[0] != "orders:read" {
+		t.Fatalf("claims = %+v", claims)
+	}
+
+	result := introspect(t, hs, clientToken)
+	if !result.Active || result.ClientId != "billing" || result.Scope != "orders:read" {
+		t.Fatalf("introspect client token = %+v", result)
+	}
+
+	// 用户token内省结果为 inactive
+	userToken, err := auth.CreateToken(Claims{UserId: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result = introspect(t, hs, userToken); result.Active {
+		t.Fatalf("introspect user token = %+v, want inactive", result)
+	}
+	if srv.Introspect(context.Background(), "garbage").Active {
+		t.Fatal("garbage token is active")
+	}
+}
+
+func TestTokenEndpointErrors(t *testing.T) {
+	_, hs := newOAuth2TestServer(t, NewAuthToken([]byte("secret")))
+
+	tests := []struct {
+		name   string
+		form   url.Values
+		status int
+		code   string
+	}{
+		{"bad secret", url.Values{"grant_type": {"client_credentials"}, "client_id": {"billing"}, "client_secret": {"x"}}, http.StatusUnauthorized, oauth2InvalidClient},
+		{"unknown scope", url.Values{"grant_type": {"client_credentials"}, "client_id": {"billing"}, "client_secret": {"billing-secret"}, "scope": {"admin"}}, http.StatusBadRequest, oauth2InvalidScope},
+		{"grant type", url.Values{"grant_type": {"password"}}, http.StatusBadRequest, oauth2UnsupportedGrantType},
+	}
+	for _, tt := range tests {
+		resp, err := http.PostForm(hs.URL+"/token", tt.form)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var e oauth2Error
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+		resp.Body.Close()
+		if resp.StatusCode != tt.status || e.Code != tt.code {
+			t.Errorf("%s: status %d error %q, want %d %q", tt.name, resp.StatusCode, e.Code, tt.status, tt.code)
+		}
+	}
+}
+
+func TestClientCredentialsSourceCaches(t *testing.T) {
+	auth := NewAuthToken([]byte("secret"))
+	srv := NewOAuth2Server(auth, StaticClients{"billing": {Secret: "billing-secret"}})
+
+	var fetches int32
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		srv.TokenHandler().ServeHTTP(w, r)
+	}))
+	t.Cleanup(hs.Close)
+
+	src := NewClientCredentialsSource(hs.URL, "billing", "billing-secret")
+	err := parallel(16, func(i int) error {
+		_, err := src.Token(context.Background())
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Fatalf("fetches = %d, want 1", n)
+	}
+
+	src.Invalidate()
+	if _, err = src.Token(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&fetches); n != 2 {
+		t.Fatalf("fetches after invalidate = %d, want 2", n)
+	}
+}
@@ -0,0 +1,182 @@
+package token
+
+import (
+	"crypto/rsa"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/xiaWave/go-common-module/cache"
+)
+
+type Options struct {
+	SigningMethod  jwt.SigningMethod // 签名算法，默认 HS256
+	SignKey        interface{}       // 签名密钥，默认为 NewAuthToken 传入的密钥
+	VerifyKey      interface{}       // 验签密钥，默认为 NewAuthToken 传入的密钥
+	ValidMethods   []string          // 解析时允许的算法，默认仅允许签名算法
+	KeySet         *KeySet           // 支持轮换的密钥集合，设置后优先于 SignKey/VerifyKey
+	KeyFunc        jwt.Keyfunc       // 自定义验签密钥查找，如 RemoteKeySet.Keyfunc
+	Cache          cache.Cache       // 存储 refresh token 等状态
+	AccessTTL      time.Duration     // access token 有效期，默认2小时
+	RefreshTTL     time.Duration     // refresh token 有效期，默认7天
+	Revoker        *Revoker          // token撤销记录，设置后解析时检查token是否已撤销
+	Extractors     []Extractor       // 按顺序尝试的token来源，默认为 Authorization Bearer 与 access_token 查询参数
+	Issuer         string            // 签发者，创建时写入 iss，解析时要求一致
+	Audience       []string          // 受众，创建时写入 aud，解析时要求至少包含其一
+	RequiredClaims []string          // 解析时必须存在的声明，如 exp、iat、sub、jti
+	Leeway         time.Duration     // 校验时间声明时允许的时钟偏差
+	Clock          func() time.Time  // 时钟，默认 time.Now
+	Encryption     *Encryption       // 设置后签名后的token再加密为 JWE，解析时只接受加密的token
+	Sessions       *SessionRegistry  // 设置后解析与刷新token时要求设备会话仍然有效
+}
+
+type Option func(o *Options)
+
+// WithSigningMethod 设置签名算法，如 jwt.SigningMethodRS256、jwt.SigningMethodES256、jwt.SigningMethodEdDSA
+func WithSigningMethod(method jwt.SigningMethod) Option {
+	return func(o *Options) {
+		o.SigningMethod = method
+	}
+}
+
+// WithSignKey 设置签名密钥，非对称算法为私钥
+func WithSignKey(key interface{}) Option {
+	return func(o *Options) {
+		o.SignKey = key
+	}
+}
+
+// WithVerifyKey 设置验签密钥，非对称算法为公钥
+func WithVerifyKey(key interface{}) Option {
+	return func(o *Options) {
+		o.VerifyKey = key
+	}
+}
+
+// WithValidMethods 设置解析时允许的算法列表，用于防止算法混淆攻击
+func WithValidMethods(methods ...string) Option {
+	return func(o *Options) {
+		o.ValidMethods = methods
+	}
+}
+
+// WithKeySet 使用密钥集合签名与验签，签名时写入 kid 头
+func WithKeySet(ks *KeySet) Option {
+	return func(o *Options) {
+		o.KeySet = ks
+	}
+}
+
+// WithKeyFunc 自定义验签密钥查找，如使用 RemoteKeySet.Keyfunc 验证其他服务签发的token
+func WithKeyFunc(fn jwt.Keyfunc) Option {
+	return func(o *Options) {
+		o.KeyFunc = fn
+	}
+}
+
+// WithCache 设置存储 refresh token 等状态的缓存
+func WithCache(c cache.Cache) Option {
+	return func(o *Options) {
+		o.Cache = c
+	}
+}
+
+// WithAccessTTL 设置 access token 有效期
+func WithAccessTTL(d time.Duration) Option {
+	return func(o *Options) {
+		o.AccessTTL = d
+	}
+}
+
+// WithRefreshTTL 设置 refresh token 有效期，每次刷新不会延长
+func WithRefreshTTL(d time.Duration) Option {
+	return func(o *Options) {
+		o.RefreshTTL = d
+	}
+}
+
+// WithRevoker 解析token时检查是否已被撤销
+func WithRevoker(r *Revoker) Option {
+	return func(o *Options) {
+		o.Revoker = r
+	}
+}
+
+// WithSessionRegistry 解析与刷新token时检查设备会话，被踢下线的设备返回 TokenInvalid
+func WithSessionRegistry(r *SessionRegistry) Option {
+	return func(o *Options) {
+		o.Sessions = r
+	}
+}
+
+// WithExtractors 设置按顺序尝试的token来源
+func WithExtractors(extractors ...Extractor) Option {
+	return func(o *Options) {
+		o.Extractors = extractors
+	}
+}
+
+// WithIssuer 设置签发者，创建token时写入 iss，解析时要求 iss 一致
+func WithIssuer(issuer string) Option {
+	return func(o *Options) {
+		o.Issuer = issuer
+	}
+}
+
+// WithAudience 设置受众，创建token时写入 aud，解析时要求 aud 至少包含其一
+func WithAudience(audience ...string) Option {
+	return func(o *Options) {
+		o.Audience = audience
+	}
+}
+
+// WithRequiredClaims 设置解析时必须存在的声明，支持 exp、iat、nbf、iss、aud、sub、jti
+func WithRequiredClaims(claims ...string) Option {
+	return func(o *Options) {
+		o.RequiredClaims = claims
+	}
+}
+
+// WithLeeway 设置校验 exp、nbf、iat 时允许的时钟偏差
+func WithLeeway(d time.Duration) Option {
+	return func(o *Options) {
+		o.Leeway = d
+	}
+}
+
+// WithClock 设置时钟，用于签发与校验时间声明
+func WithClock(clock func() time.Time) Option {
+	return func(o *Options) {
+		o.Clock = clock
+	}
+}
+
+// WithEncryption 签名后再加密token，负载对客户端不可读
+func WithEncryption(enc *Encryption) Option {
+	return func(o *Options) {
+		o.Encryption = enc
+	}
+}
+
+// WithDirectEncryption 使用32字节对称密钥以 dir/A256GCM 加密token
+func WithDirectEncryption(key []byte) Option {
+	return WithEncryption(&Encryption{
+		Algorithm:  jose.DIRECT,
+		EncryptKey: key,
+		DecryptKey: key,
+	})
+}
+
+// WithRSAEncryption 使用 RSA-OAEP/A256GCM 加密token，只签发或只解析时可传 nil
+func WithRSAEncryption(pub *rsa.PublicKey, priv *rsa.PrivateKey) Option {
+	enc := &Encryption{Algorithm: jose.RSA_OAEP}
+	// 避免 nil 指针存入接口后不等于 nil
+	if pub != nil {
+		enc.EncryptKey = pub
+	}
+	if priv != nil {
+		enc.DecryptKey = priv
+	}
+	return WithEncryption(enc)
+}
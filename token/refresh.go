@@ -0,0 +1,231 @@
+package token
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+var ErrCacheRequired = errors.New("cache is required")
+
+// TokenPair access token 与 refresh token
+type TokenPair struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresAt        int64  `json:"expires_at"`         // access token 过期时间戳
+	RefreshExpiresAt int64  `json:"refresh_expires_at"` // refresh token 过期时间戳
+}
+
+// refreshFamily 同一次登录轮换出的 refresh token 共享一个家族
+type refreshFamily struct {
+	Claims    Claims `json:"claims"`
+	LoginAt   int64  `json:"login_at"` // 登录时间，微秒
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// loginClaims 以登录时间作为签发时间，用于检查整个家族是否已被按用户或单点用户撤销
+func (f *refreshFamily) loginClaims() *Claims {
+	claims := f.Claims
+	claims.ID = ""
+	claims.IssuedAt = nil
+	claims.IssuedAtMicro = 0
+	if f.LoginAt > 0 {
+		claims.IssuedAt = jwt.NewNumericDate(time.UnixMicro(f.LoginAt))
+		claims.IssuedAtMicro = f.LoginAt
+	}
+	return &claims
+}
+
+// CreateTokenPair 签发 access token 与 refresh token，并创建新的 refresh token 家族
+func (p *AuthToken) CreateTokenPair(ctx context.Context, claims Claims) (*TokenPair, error) {
+	if p.opts.Cache == nil {
+		return nil, ErrCacheRequired
+	}
+
+	familyID, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
+
+	now := p.opts.Clock()
+	family := refreshFamily{
+		Claims:    claims,
+		LoginAt:   now.UnixMicro(),
+		ExpiresAt: now.Add(p.opts.RefreshTTL).Unix(),
+	}
+	data, err := json.Marshal(family)
+	if err != nil {
+		return nil, err
+	}
+	if err = p.opts.Cache.Set(ctx, refreshFamilyKey+familyID, string(data), p.opts.RefreshTTL); err != nil {
+		return nil, err
+	}
+
+	return p.issuePair(ctx, familyID, family)
+}
+
+// RefreshToken 使用 refresh token 换取新的 token 对，旧的 refresh token 立即失效；
+// 已使用过的 refresh token 被重放、或登录后用户被撤销时撤销整个家族并返回 TokenInvalid
+func (p *AuthToken) RefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	if p.opts.Cache == nil {
+		return nil, ErrCacheRequired
+	}
+
+	familyID, ok := parseRefreshToken(refreshToken)
+	if !ok {
+		return nil, error_codes.TokenInvalidError
+	}
+
+	// GetDel 保证同一个 refresh token 只能被使用一次
+	val, err := p.opts.Cache.GetDel(ctx, refreshTokenKey+hashToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+
+	family, err := p.loadFamily(ctx, familyID)
+	if err != nil {
+		return nil, err
+	}
+	if family == nil {
+		return nil, error_codes.TokenInvalidError
+	}
+
+	if fmt.Sprint(val) != familyID {
+		// 重放已使用的 refresh token，撤销整个家族
+		if err = p.opts.Cache.Delete(ctx, refreshFamilyKey+familyID); err != nil {
+			return nil, err
+		}
+		return nil, error_codes.TokenInvalidError
+	}
+
+	// 登录后按用户或单点用户撤销过时不再续期
+	if p.opts.Revoker != nil {
+		revoked, err := p.opts.Revoker.IsRevoked(ctx, family.loginClaims())
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			if err = p.opts.Cache.Delete(ctx, refreshFamilyKey+familyID); err != nil {
+				return nil, err
+			}
+			return nil, error_codes.TokenInvalidError
+		}
+	}
+
+	// 设备已被踢下线时不再续期
+	if p.opts.Sessions != nil {
+		active, err := p.opts.Sessions.IsActive(ctx, &family.Claims)
+		if err != nil {
+			return nil, err
+		}
+		if !active {
+			if err = p.opts.Cache.Delete(ctx, refreshFamilyKey+familyID); err != nil {
+				return nil, err
+			}
+			return nil, error_codes.TokenInvalidError
+		}
+	}
+
+	return p.issuePair(ctx, familyID, *family)
+}
+
+// RevokeRefreshToken 撤销 refresh token 所在的家族，用于退出登录
+func (p *AuthToken) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	if p.opts.Cache == nil {
+		return ErrCacheRequired
+	}
+
+	familyID, ok := parseRefreshToken(refreshToken)
+	if !ok {
+		return error_codes.TokenInvalidError
+	}
+
+	return p.opts.Cache.Delete(ctx, refreshFamilyKey+familyID, refreshTokenKey+hashToken(refreshToken))
+}
+
+func (p *AuthToken) issuePair(ctx context.Context, familyID string, family refreshFamily) (*TokenPair, error) {
+	now := p.opts.Clock()
+	refreshExpiresAt := time.Unix(family.ExpiresAt, 0)
+	ttl := refreshExpiresAt.Sub(now)
+	if ttl <= 0 {
+		return nil, error_codes.TokenInvalidError
+	}
+
+	claims := family.Claims
+	claims.ID = ""
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.IssuedAtMicro = now.UnixMicro()
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(p.opts.AccessTTL))
+	accessToken, err := p.CreateToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken := familyID + "." + secret
+	if err = p.opts.Cache.Set(ctx, refreshTokenKey+hashToken(refreshToken), familyID, ttl); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresAt:        claims.ExpiresAt.Unix(),
+		RefreshExpiresAt: refreshExpiresAt.Unix(),
+	}, nil
+}
+
+func (p *AuthToken) loadFamily(ctx context.Context, familyID string) (*refreshFamily, error) {
+	val, err := p.opts.Cache.Get(ctx, refreshFamilyKey+familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := fmt.Sprint(val)
+	if data == "" {
+		return nil, nil
+	}
+
+	family := &refreshFamily{}
+	if err = json.Unmarshal([]byte(data), family); err != nil {
+		return nil, err
+	}
+	return family, nil
+}
+
+// parseRefreshToken 解析 refresh token 中的家族id
+func parseRefreshToken(refreshToken string) (string, bool) {
+	familyID, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok || familyID == "" || secret == "" {
+		return "", false
+	}
+	return familyID, true
+}
+
+// hashToken 缓存中只保存 refresh token 的摘要
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,185 @@
+package token
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/cast"
+
+	"github.com/xiaWave/go-common-module/cache"
+)
+
+type RevokerOptions struct {
+	LocalSize     int           // 本地LRU缓存条目数，默认10000
+	LocalTTL      time.Duration // 本地缓存未撤销结果的时间，撤销在其他实例上最多延迟该时间生效，默认10秒
+	RevokeUserTTL time.Duration // 按用户撤销记录的保留时间，应不小于token的最长有效期，默认7天
+}
+
+type RevokerOption func(o *RevokerOptions)
+
+func WithLocalSize(size int) RevokerOption {
+	return func(o *RevokerOptions) {
+		o.LocalSize = size
+	}
+}
+
+func WithLocalTTL(d time.Duration) RevokerOption {
+	return func(o *RevokerOptions) {
+		o.LocalTTL = d
+	}
+}
+
+func WithRevokeUserTTL(d time.Duration) RevokerOption {
+	return func(o *RevokerOptions) {
+		o.RevokeUserTTL = d
+	}
+}
+
+// Revoker 基于 cache.Cache 的token撤销记录，支持按 jti 撤销与按用户撤销某时间点之前签发的token
+type Revoker struct {
+	cache cache.Cache
+	local *lru
+	opts  RevokerOptions
+}
+
+func NewRevoker(c cache.Cache, opts ...RevokerOption) *Revoker {
+	o := RevokerOptions{
+		LocalSize:     10000,
+		LocalTTL:      10 * time.Second,
+		RevokeUserTTL: defaultRefreshTTL,
+	}
+	for _, f := range opts {
+		f(&o)
+	}
+
+	return &Revoker{
+		cache: c,
+		local: newLRU(o.LocalSize),
+		opts:  o,
+	}
+}
+
+// Revoke 撤销指定 jti 的token，记录保留到token过期
+func (r *Revoker) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := r.cache.Set(ctx, revokedJtiKey+jti, "1", ttl); err != nil {
+		return err
+	}
+
+	r.local.Set(revokedJtiKey+jti, true, ttl)
+	return nil
+}
+
+// RevokeUser 撤销用户在 before 之前签发的所有token，用于修改密码、强制下线等场景
+func (r *Revoker) RevokeUser(ctx context.Context, userId int64, before time.Time) error {
+	return r.revokeBefore(ctx, revokedUserKey+strconv.FormatInt(userId, 10), before)
+}
+
+// RevokeSign 撤销单点用户在 before 之前于所有应用签发的token，用于单点全局退出
+func (r *Revoker) RevokeSign(ctx context.Context, signId string, before time.Time) error {
+	return r.revokeBefore(ctx, revokedSignKey+signId, before)
+}
+
+// revokeBefore 记录微秒精度的撤销时间点
+func (r *Revoker) revokeBefore(ctx context.Context, key string, before time.Time) error {
+	until := before.UnixMicro()
+	if err := r.cache.Set(ctx, key, until, r.opts.RevokeUserTTL); err != nil {
+		return err
+	}
+
+	r.local.Set(key, until, r.opts.LocalTTL)
+	return nil
+}
+
+// IsRevoked token是否已被撤销，按用户、单点用户撤销分别对实现了 UserClaims、SignClaims 的 Claims 生效
+func (r *Revoker) IsRevoked(ctx context.Context, claims jwt.Claims) (bool, error) {
+	if rc := registeredClaims(claims); rc != nil && rc.ID != "" {
+		revoked, err := r.jtiRevoked(ctx, rc.ID)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	var keys []string
+	if uc, ok := claims.(UserClaims); ok {
+		keys = append(keys, revokedUserKey+strconv.FormatInt(uc.GetUserId(), 10))
+	}
+	if sc, ok := claims.(SignClaims); ok && sc.GetSignId() != "" {
+		keys = append(keys, revokedSignKey+sc.GetSignId())
+	}
+	if len(keys) == 0 {
+		return false, nil
+	}
+
+	iat, err := issuedAtMicro(claims)
+	if err != nil {
+		return false, err
+	}
+
+	for _, key := range keys {
+		before, err := r.revokedBefore(ctx, key)
+		if err != nil {
+			return false, err
+		}
+		if before > 0 && iat < before {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// issuedAtMicro 返回微秒精度的签发时间，未实现 IssuedAtMicroClaims 时取 iat 所在秒的开始，
+// 撤销时间点所在这一秒内签发的token视为已撤销；未设置 iat 时返回 0
+func issuedAtMicro(claims jwt.Claims) (int64, error) {
+	iat, err := claims.GetIssuedAt()
+	if err != nil || iat == nil {
+		return 0, err
+	}
+
+	// 微秒签发时间与 iat 不在同一秒时以 iat 为准
+	if mc, ok := claims.(IssuedAtMicroClaims); ok {
+		if us := mc.GetIssuedAtMicro(); us/1e6 == iat.Unix() {
+			return us, nil
+		}
+	}
+	return iat.Unix() * 1e6, nil
+}
+
+func (r *Revoker) jtiRevoked(ctx context.Context, jti string) (bool, error) {
+	key := revokedJtiKey + jti
+	if val, ok := r.local.Get(key); ok {
+		return val.(bool), nil
+	}
+
+	val, err := r.cache.Get(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	revoked := cast.ToString(val) != ""
+	r.local.Set(key, revoked, r.opts.LocalTTL)
+	return revoked, nil
+}
+
+// revokedBefore 返回按用户或单点用户撤销的微秒时间点，未撤销时为 0
+func (r *Revoker) revokedBefore(ctx context.Context, key string) (int64, error) {
+	if val, ok := r.local.Get(key); ok {
+		return val.(int64), nil
+	}
+
+	val, err := r.cache.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+
+	before := cast.ToInt64(val)
+	r.local.Set(key, before, r.opts.LocalTTL)
+	return before, nil
+}
@@ -0,0 +1,276 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/xiaWave/go-common-module/cache"
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+var ErrSessionLocked = errors.New("user sessions are being modified")
+
+// SessionPolicy 超出最大会话数时的处理策略
+type SessionPolicy int
+
+const (
+	// EvictOldest 踢掉最早登录的设备
+	EvictOldest SessionPolicy = iota
+	// RejectNew 拒绝新设备登录
+	RejectNew
+)
+
+type SessionOptions struct {
+	MaxSessions int              // 每个用户最多同时登录的设备数，默认3，0 表示不限制
+	Policy      SessionPolicy    // 超出时的处理策略，默认踢掉最早登录的设备
+	TTL         time.Duration    // 会话有效期，应与 refresh token 有效期一致，默认7天
+	Clock       func() time.Time // 时钟，默认 time.Now
+}
+
+type SessionOption func(o *SessionOptions)
+
+func WithMaxSessions(n int) SessionOption {
+	return func(o *SessionOptions) {
+		o.MaxSessions = n
+	}
+}
+
+func WithSessionPolicy(policy SessionPolicy) SessionOption {
+	return func(o *SessionOptions) {
+		o.Policy = policy
+	}
+}
+
+func WithDeviceSessionTTL(d time.Duration) SessionOption {
+	return func(o *SessionOptions) {
+		o.TTL = d
+	}
+}
+
+func WithSessionClock(clock func() time.Time) SessionOption {
+	return func(o *SessionOptions) {
+		o.Clock = clock
+	}
+}
+
+// Session 用户在某个设备上的登录会话
+type Session struct {
+	Id        string `json:"id"` // 会话id，每次登录重新生成
+	DeviceId  string `json:"device_id"`
+	LoginAt   int64  `json:"login_at"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// SessionRegistry 基于 cache.Cache 记录每个用户的活跃设备，用于限制并发登录与踢下线，cache 需实现 cache.Atomic
+type SessionRegistry struct {
+	cache cache.Cache
+	opts  SessionOptions
+}
+
+func NewSessionRegistry(c cache.Cache, opts ...SessionOption) *SessionRegistry {
+	o := SessionOptions{
+		MaxSessions: 3,
+		Policy:      EvictOldest,
+		TTL:         defaultRefreshTTL,
+		Clock:       time.Now,
+	}
+	for _, f := range opts {
+		f(&o)
+	}
+
+	return &SessionRegistry{
+		cache: c,
+		opts:  o,
+	}
+}
+
+// Login 登记设备会话，签发token前调用，返回的会话id需写入 Claims.SessionId；
+// 同一设备重复登录时替换旧会话，旧会话的token随即失效。
+// 超出最大会话数时按策略踢掉最早登录的设备并返回被踢掉的会话，或返回 AccessDenied
+func (r *SessionRegistry) Login(ctx context.Context, userId int64, deviceId string) (*Session, []Session, error) {
+	if deviceId == "" {
+		return nil, nil, error_codes.New(error_codes.InvalidParam, "设备id为空")
+	}
+
+	sid, err := randomString(16)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		session Session
+		evicted []Session
+	)
+	err = r.update(ctx, userId, func(sessions []Session) ([]Session, error) {
+		now := r.opts.Clock()
+		sessions = removeDevice(sessions, deviceId)
+
+		if r.opts.MaxSessions > 0 && len(sessions) >= r.opts.MaxSessions {
+			if r.opts.Policy == RejectNew {
+				return nil, error_codes.New(error_codes.AccessDenied, "登录设备数已达上限")
+			}
+			n := len(sessions) - r.opts.MaxSessions + 1
+			evicted = append(evicted, sessions[:n]...)
+			sessions = sessions[n:]
+		}
+
+		session = Session{
+			Id:        sid,
+			DeviceId:  deviceId,
+			LoginAt:   now.Unix(),
+			ExpiresAt: now.Add(r.opts.TTL).Unix(),
+		}
+		return append(sessions, session), nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &session, evicted, nil
+}
+
+// Logout 移除设备会话，该设备的token随即失效
+func (r *SessionRegistry) Logout(ctx context.Context, userId int64, deviceId string) error {
+	return r.update(ctx, userId, func(sessions []Session) ([]Session, error) {
+		return removeDevice(sessions, deviceId), nil
+	})
+}
+
+// LogoutAll 移除用户的所有会话
+func (r *SessionRegistry) LogoutAll(ctx context.Context, userId int64) error {
+	return r.cache.Delete(ctx, userSessionsKey+strconv.FormatInt(userId, 10))
+}
+
+// Sessions 返回用户的活跃会话，按登录时间升序
+func (r *SessionRegistry) Sessions(ctx context.Context, userId int64) ([]Session, error) {
+	return r.load(ctx, userId)
+}
+
+// IsActive token所属设备会话是否仍然有效，设备重新登录后旧会话的token同样无效；
+// 未实现 DeviceClaims 或未携带设备id、会话id的token视为无效
+func (r *SessionRegistry) IsActive(ctx context.Context, claims jwt.Claims) (bool, error) {
+	dc, ok := claims.(DeviceClaims)
+	if !ok || dc.GetDeviceId() == "" || dc.GetSessionId() == "" {
+		return false, nil
+	}
+
+	sessions, err := r.load(ctx, dc.GetUserId())
+	if err != nil {
+		return false, err
+	}
+	for _, s := range sessions {
+		if s.DeviceId == dc.GetDeviceId() && s.Id == dc.GetSessionId() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// update 加锁后读取、修改并保存用户会话，避免并发登录时互相覆盖
+func (r *SessionRegistry) update(ctx context.Context, userId int64, fn func([]Session) ([]Session, error)) error {
+	id := strconv.FormatInt(userId, 10)
+	release, err := r.lock(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	sessions, err := r.load(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if sessions, err = fn(sessions); err != nil {
+		return err
+	}
+
+	if len(sessions) == 0 {
+		return r.cache.Delete(ctx, userSessionsKey+id)
+	}
+	data, err := json.Marshal(sessions)
+	if err != nil {
+		return err
+	}
+	return r.cache.Set(ctx, userSessionsKey+id, string(data), r.opts.TTL)
+}
+
+// lock 获取用户会话锁，锁的值为随机串，释放时只删除自己持有的锁，
+// 避免修改超过锁有效期后删除其他请求持有的锁
+func (r *SessionRegistry) lock(ctx context.Context, id string) (func(), error) {
+	atomic, err := cache.AsAtomic(r.cache)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
+
+	key := userSessionsLockKey + id
+	release := func() {
+		_, _ = atomic.CompareAndDelete(context.Background(), key, owner)
+	}
+
+	for i := 0; i < 50; i++ {
+		ok, err := atomic.SetNX(ctx, key, owner, 5*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return release, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+	return nil, ErrSessionLocked
+}
+
+// load 读取用户会话并去掉已过期的会话
+func (r *SessionRegistry) load(ctx context.Context, userId int64) ([]Session, error) {
+	val, err := r.cache.Get(ctx, userSessionsKey+strconv.FormatInt(userId, 10))
+	if err != nil {
+		return nil, err
+	}
+
+	data := fmt.Sprint(val)
+	if data == "" {
+		return nil, nil
+	}
+
+	var sessions []Session
+	if err = json.Unmarshal([]byte(data), &sessions); err != nil {
+		return nil, err
+	}
+
+	now := r.opts.Clock().Unix()
+	active := sessions[:0]
+	for _, s := range sessions {
+		if s.ExpiresAt > now {
+			active = append(active, s)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].LoginAt < active[j].LoginAt
+	})
+	return active, nil
+}
+
+func removeDevice(sessions []Session, deviceId string) []Session {
+	out := sessions[:0]
+	for _, s := range sessions {
+		if s.DeviceId != deviceId {
+			out = append(out, s)
+		}
+	}
+	return out
+}
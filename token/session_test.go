@@ -0,0 +1,149 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+func TestSessionRegistryEvictOldest(t *testing.T) {
+	ctx := context.Background()
+	c := newMemCache()
+	now := time.Now()
+	clock := func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	sessions := NewSessionRegistry(c, WithMaxSessions(2), WithSessionClock(clock))
+	auth := NewAuthToken([]byte("secret"), WithCache(c), WithSessionRegistry(sessions))
+
+	pairs := make(map[string]*TokenPair)
+	for _, device := range []string{"phone", "pad", "pc"} {
+		session, _, err := sessions.Login(ctx, 1, device)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pair, err := auth.CreateTokenPair(ctx, Claims{UserId: 1, DeviceId: device, SessionId: session.Id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pairs[device] = pair
+	}
+
+	if _, err := auth.ParseToken(pairs["phone"].AccessToken); !errors.Is(err, error_codes.TokenInvalidError) {
+		t.Fatalf("evicted device: err = %v, want TokenInvalid", err)
+	}
+	if _, err := auth.RefreshToken(ctx, pairs["phone"].RefreshToken); !errors.Is(err, error_codes.TokenInvalidError) {
+		t.Fatalf("refresh evicted device: err = %v, want TokenInvalid", err)
+	}
+	for _, device := range []string{"pad", "pc"} {
+		if _, err := auth.ParseToken(pairs[device].AccessToken); err != nil {
+			t.Fatalf("%s: %v", device, err)
+		}
+	}
+}
+
+func TestSessionRegistryRejectNew(t *testing.T) {
+	ctx := context.Background()
+	sessions := NewSessionRegistry(newMemCache(), WithMaxSessions(1), WithSessionPolicy(RejectNew))
+
+	if _, _, err := sessions.Login(ctx, 1, "phone"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := sessions.Login(ctx, 1, "pc"); error_codes.Code(err) != error_codes.AccessDenied {
+		t.Fatalf("err = %v, want AccessDenied", err)
+	}
+	// 同一设备重复登录不占用新的会话
+	if _, _, err := sessions.Login(ctx, 1, "phone"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSessionRegistryReloginSameDevice(t *testing.T) {
+	ctx := context.Background()
+	c := newMemCache()
+	sessions := NewSessionRegistry(c)
+	auth := NewAuthToken([]byte("secret"), WithCache(c), WithSessionRegistry(sessions))
+
+	login := func() *TokenPair {
+		session, _, err := sessions.Login(ctx, 1, "phone")
+		if err != nil {
+			t.Fatal(err)
+		}
+		pair, err := auth.CreateTokenPair(ctx, Claims{UserId: 1, DeviceId: "phone", SessionId: session.Id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pair
+	}
+
+	old := login()
+	if err := sessions.Logout(ctx, 1, "phone"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.ParseToken(old.AccessToken); !errors.Is(err, error_codes.TokenInvalidError) {
+		t.Fatalf("after logout: err = %v, want TokenInvalid", err)
+	}
+
+	// 同一设备重新登录后，退出前的token不能恢复有效
+	current := login()
+	if _, err := auth.ParseToken(old.AccessToken); !errors.Is(err, error_codes.TokenInvalidError) {
+		t.Fatalf("after re-login: err = %v, want TokenInvalid", err)
+	}
+	if _, err := auth.RefreshToken(ctx, old.RefreshToken); !errors.Is(err, error_codes.TokenInvalidError) {
+		t.Fatalf("refresh after re-login: err = %v, want TokenInvalid", err)
+	}
+	if _, err := auth.ParseToken(current.AccessToken); err != nil {
+		t.Fatalf("current session: %v", err)
+	}
+
+	// 未登出直接在同一设备重新登录同样替换旧会话
+	login()
+	if _, err := auth.ParseToken(current.AccessToken); !errors.Is(err, error_codes.TokenInvalidError) {
+		t.Fatalf("replaced session: err = %v, want TokenInvalid", err)
+	}
+}
+
+func TestSessionRegistryConcurrentLogin(t *testing.T) {
+	ctx := context.Background()
+	sessions := NewSessionRegistry(newMemCache(), WithMaxSessions(100))
+
+	err := parallel(20, func(i int) error {
+		_, _, err := sessions.Login(ctx, 1, fmt.Sprintf("device-%d", i))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	active, err := sessions.Sessions(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 20 {
+		t.Fatalf("%d sessions, want 20", len(active))
+	}
+}
+
+func TestSessionLockReleaseKeepsOtherOwner(t *testing.T) {
+	ctx := context.Background()
+	c := newMemCache()
+	sessions := NewSessionRegistry(c)
+
+	release, err := sessions.lock(ctx, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 模拟锁过期后被其他请求获取
+	_ = c.Set(ctx, userSessionsLockKey+"1", "other", time.Minute)
+	release()
+
+	if v, _ := c.Get(ctx, userSessionsLockKey+"1"); v != "other" {
+		t.Fatalf("lock value = %v, want other owner's lock kept", v)
+	}
+}
@@ -0,0 +1,208 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/cast"
+
+	"github.com/xiaWave/go-common-module/cache"
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+// ticket 单点登录票据内容
+type ticket struct {
+	SignId string `json:"sign_id"`
+	App    string `json:"app"`
+}
+
+// LogoutHandler 全局退出时通知应用
+type LogoutHandler func(ctx context.Context, signId string) error
+
+// UserResolver 将单点用户id映射为应用内部用户id
+type UserResolver func(ctx context.Context, signId string) (int64, error)
+
+type SSOOptions struct {
+	TicketTTL  time.Duration    // 票据有效期，默认1分钟
+	SessionTTL time.Duration    // 应用会话记录有效期，应不小于应用token有效期，默认7天
+	Clock      func() time.Time // 时钟，默认 time.Now
+}
+
+type SSOOption func(o *SSOOptions)
+
+func WithTicketTTL(d time.Duration) SSOOption {
+	return func(o *SSOOptions) {
+		o.TicketTTL = d
+	}
+}
+
+func WithSessionTTL(d time.Duration) SSOOption {
+	return func(o *SSOOptions) {
+		o.SessionTTL = d
+	}
+}
+
+func WithSSOClock(clock func() time.Time) SSOOption {
+	return func(o *SSOOptions) {
+		o.Clock = clock
+	}
+}
+
+func newSSOOptions(opts ...SSOOption) SSOOptions {
+	o := SSOOptions{
+		TicketTTL:  time.Minute,
+		SessionTTL: defaultRefreshTTL,
+		Clock:      time.Now,
+	}
+	for _, f := range opts {
+		f(&o)
+	}
+	return o
+}
+
+// SSOServer 单点登录服务端，签发一次性票据并负责全局退出
+type SSOServer struct {
+	cache    cache.Cache
+	revoker  *Revoker
+	opts     SSOOptions
+	mu       sync.RWMutex
+	handlers map[string]LogoutHandler
+}
+
+// NewSSOServer cache 需与各应用共享，用于票据兑换与全局退出
+func NewSSOServer(c cache.Cache, opts ...SSOOption) *SSOServer {
+	return &SSOServer{
+		cache:    c,
+		revoker:  NewRevoker(c),
+		opts:     newSSOOptions(opts...),
+		handlers: make(map[string]LogoutHandler),
+	}
+}
+
+// RegisterApp 注册应用，handler 可为空，全局退出时通知已兑换过票据的应用
+func (s *SSOServer) RegisterApp(app string, handler LogoutHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[app] = handler
+}
+
+// IssueTicket 为已登录的单点用户签发访问应用的一次性票据
+func (s *SSOServer) IssueTicket(ctx context.Context, signId string, app string) (string, error) {
+	id, err := randomString(24)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(ticket{SignId: signId, App: app})
+	if err != nil {
+		return "", err
+	}
+
+	if err = s.cache.Set(ctx, ssoTicketKey+id, string(data), s.opts.TicketTTL); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// Logout 全局退出：撤销单点用户此前在所有应用签发的token，并通知兑换过票据的应用；
+// 部分应用通知失败时仍会通知其余应用，失败的应用保留会话记录以便重试
+func (s *SSOServer) Logout(ctx context.Context, signId string) error {
+	if err := s.revoker.RevokeSign(ctx, signId, s.opts.Clock()); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	errs := error_codes.NewMultiError()
+	for app, handler := range s.handlers {
+		errs.Add(app, s.notifyLogout(ctx, signId, app, handler))
+	}
+
+	return errs.Err()
+}
+
+func (s *SSOServer) notifyLogout(ctx context.Context, signId string, app string, handler LogoutHandler) error {
+	key := ssoSessionKey(signId, app)
+	val, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if cast.ToString(val) == "" {
+		return nil
+	}
+
+	if handler != nil {
+		if err = handler(ctx, signId); err != nil {
+			return err
+		}
+	}
+	return s.cache.Delete(ctx, key)
+}
+
+// SSOClient 单点登录应用端，使用票据兑换本地会话
+type SSOClient struct {
+	app     string
+	cache   cache.Cache
+	auth    *AuthToken
+	resolve UserResolver
+	opts    SSOOptions
+}
+
+// NewSSOClient auth 应配置与 SSOServer 共享 cache 的 Revoker，全局退出后其签发的token才会失效
+func NewSSOClient(app string, c cache.Cache, auth *AuthToken, resolve UserResolver, opts ...SSOOption) *SSOClient {
+	return &SSOClient{
+		app:     app,
+		cache:   c,
+		auth:    auth,
+		resolve: resolve,
+		opts:    newSSOOptions(opts...),
+	}
+}
+
+// Exchange 兑换票据，签发绑定单点用户id的本地token，票据只能使用一次
+func (c *SSOClient) Exchange(ctx context.Context, ticketId string) (string, error) {
+	val, err := c.cache.GetDel(ctx, ssoTicketKey+ticketId)
+	if err != nil {
+		return "", err
+	}
+
+	data := cast.ToString(val)
+	if data == "" {
+		return "", error_codes.InvalidTicketError
+	}
+
+	t := ticket{}
+	if err = json.Unmarshal([]byte(data), &t); err != nil || t.App != c.app {
+		return "", error_codes.InvalidTicketError
+	}
+
+	userId, err := c.resolve(ctx, t.SignId)
+	if err != nil {
+		return "", err
+	}
+
+	now := c.auth.opts.Clock()
+	claims := Claims{UserId: userId, SignId: t.SignId}
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.IssuedAtMicro = now.UnixMicro()
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(c.auth.opts.AccessTTL))
+	tokenString, err := c.auth.CreateToken(claims)
+	if err != nil {
+		return "", err
+	}
+
+	if err = c.cache.Set(ctx, ssoSessionKey(t.SignId, c.app), "1", c.opts.SessionTTL); err != nil {
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
+func ssoSessionKey(signId string, app string) string {
+	return ssoSessionKeyPrefix + signId + ":" + app
+}
@@ -0,0 +1,160 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+// logoutRecorder 记录收到全局退出通知的应用，fail 中的应用通知失败
+type logoutRecorder struct {
+	mu       sync.Mutex
+	notified []string
+	fail     map[string]error
+}
+
+func (r *logoutRecorder) handler(app string) LogoutHandler {
+	return func(ctx context.Context, signId string) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if err := r.fail[app]; err != nil {
+			return err
+		}
+		r.notified = append(r.notified, app)
+		return nil
+	}
+}
+
+func (r *logoutRecorder) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	apps := r.notified
+	r.notified = nil
+	sort.Strings(apps)
+	return apps
+}
+
+func newSSOClient(app string, c *memCache) *SSOClient {
+	auth := NewAuthToken([]byte(app+"-secret"), WithRevoker(NewRevoker(c)))
+	return NewSSOClient(app, c, auth, func(ctx context.Context, signId string) (int64, error) {
+		return 42, nil
+	})
+}
+
+func TestSSOTicketSingleUse(t *testing.T) {
+	ctx := context.Background()
+	c := newMemCache()
+	server := NewSSOServer(c)
+	client := newSSOClient("a", c)
+
+	ticket, err := server.IssueTicket(ctx, "s-1", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenString, err := client.Exchange(ctx, ticket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := client.auth.ParseToken(tokenString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserId != 42 || claims.SignId != "s-1" {
+		t.Fatalf("claims = %+v", claims)
+	}
+
+	if _, err = client.Exchange(ctx, ticket); !errors.Is(err, error_codes.InvalidTicketError) {
+		t.Fatalf("second exchange: err = %v, want InvalidTicket", err)
+	}
+}
+
+func TestSSOTicketOtherApp(t *testing.T) {
+	ctx := context.Background()
+	c := newMemCache()
+	server := NewSSOServer(c)
+
+	ticket, err := server.IssueTicket(ctx, "s-1", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = newSSOClient("b", c).Exchange(ctx, ticket); !errors.Is(err, error_codes.InvalidTicketError) {
+		t.Fatalf("exchange by other app: err = %v, want InvalidTicket", err)
+	}
+	// 兑换失败同样消耗票据
+	if _, err = newSSOClient("a", c).Exchange(ctx, ticket); !errors.Is(err, error_codes.InvalidTicketError) {
+		t.Fatalf("exchange after rejection: err = %v, want InvalidTicket", err)
+	}
+}
+
+func TestSSOLogout(t *testing.T) {
+	ctx := context.Background()
+	c := newMemCache()
+	server := NewSSOServer(c)
+
+	errNotify := errors.New("notify failed")
+	recorder := &logoutRecorder{fail: map[string]error{"b": errNotify}}
+	clients := make(map[string]*SSOClient)
+	for _, app := range []string{"a", "b", "c"} {
+		server.RegisterApp(app, recorder.handler(app))
+		clients[app] = newSSOClient(app, c)
+	}
+
+	// 只有 a、b 兑换过票据
+	tokens := make(map[string]string)
+	for _, app := range []string{"a", "b"} {
+		ticket, err := server.IssueTicket(ctx, "s-1", app)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tokens[app], err = clients[app].Exchange(ctx, ticket); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := server.Logout(ctx, "s-1")
+	if !errors.Is(err, errNotify) {
+		t.Fatalf("logout: err = %v, want notify error", err)
+	}
+	if got := recorder.take(); len(got) != 1 || got[0] != "a" {
+		t.Fatalf("notified = %v, want [a]", got)
+	}
+
+	// 退出后各应用签发的token均失效
+	for app, tokenString := range tokens {
+		if _, err = clients[app].auth.ParseToken(tokenString); !errors.Is(err, error_codes.TokenInvalidError) {
+			t.Fatalf("%s token after logout: err = %v, want TokenInvalid", app, err)
+		}
+	}
+
+	// 通知失败的应用保留会话记录，重试时只通知该应用
+	recorder.fail = nil
+	if err = server.Logout(ctx, "s-1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := recorder.take(); len(got) != 1 || got[0] != "b" {
+		t.Fatalf("retry notified = %v, want [b]", got)
+	}
+	if err = server.Logout(ctx, "s-1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := recorder.take(); len(got) != 0 {
+		t.Fatalf("third logout notified = %v, want none", got)
+	}
+
+	// 退出后重新登录签发的token有效
+	ticket, err := server.IssueTicket(ctx, "s-1", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenString, err := clients["a"].Exchange(ctx, ticket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = clients["a"].auth.ParseToken(tokenString); err != nil {
+		t.Fatalf("token after re-login: %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+package verifycode
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"github.com/xiaWave/go-common-module/cache"
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+// 验证码相关 key
+const (
+	codeKey     = "verifycode:code:"
+	attemptsKey = "verifycode:attempts:"
+	cooldownKey = "verifycode:cooldown:"
+	dailyKey    = "verifycode:daily:"
+)
+
+type Options struct {
+	Digits      int              // 验证码位数，默认6位
+	TTL         time.Duration    // 有效期，默认5分钟
+	MaxAttempts int              // 每个验证码最多校验次数，默认5次
+	Cooldown    time.Duration    // 重新发送的间隔，默认60秒
+	DailyLimit  int              // 每个目标每天最多发送次数，默认10次，0 表示不限制
+	Clock       func() time.Time // 时钟，默认 time.Now
+}
+
+type Option func(o *Options)
+
+func WithDigits(digits int) Option {
+	return func(o *Options) {
+		o.Digits = digits
+	}
+}
+
+func WithTTL(ttl time.Duration) Option {
+	return func(o *Options) {
+		o.TTL = ttl
+	}
+}
+
+func WithMaxAttempts(n int) Option {
+	return func(o *Options) {
+		o.MaxAttempts = n
+	}
+}
+
+func WithCooldown(d time.Duration) Option {
+	return func(o *Options) {
+		o.Cooldown = d
+	}
+}
+
+func WithDailyLimit(n int) Option {
+	return func(o *Options) {
+		o.DailyLimit = n
+	}
+}
+
+func WithClock(clock func() time.Time) Option {
+	return func(o *Options) {
+		o.Clock = clock
+	}
+}
+
+// Manager 基于 cache.Cache 的验证码管理，按 (场景, 手机号/邮箱) 维度生成与校验验证码，cache 需实现 cache.Atomic
+type Manager struct {
+	cache  cache.Cache
+	sender Sender
+	opts   Options
+}
+
+func NewManager(c cache.Cache, sender Sender, opts ...Option) *Manager {
+	o := Options{
+		Digits:      6,
+		TTL:         5 * time.Minute,
+		MaxAttempts: 5,
+		Cooldown:    time.Minute,
+		DailyLimit:  10,
+		Clock:       time.Now,
+	}
+	for _, f := range opts {
+		f(&o)
+	}
+
+	return &Manager{
+		cache:  c,
+		sender: sender,
+		opts:   o,
+	}
+}
+
+// Send 生成并发送验证码，发送过于频繁或超出每日上限时返回 LimitExceed
+func (m *Manager) Send(ctx context.Context, scene, target string) error {
+	if target == "" {
+		return error_codes.NewWithCode(error_codes.PhoneEmpty)
+	}
+	id := scene + ":" + target
+
+	atomic, err := cache.AsAtomic(m.cache)
+	if err != nil {
+		return error_codes.NewWithError(error_codes.CacheErr, "", err)
+	}
+
+	now := m.opts.Clock()
+	y, mo, d := now.Date()
+	tomorrow := time.Date(y, mo, d+1, 0, 0, 0, 0, now.Location())
+	daily := dailyKey + id + ":" + now.Format("20060102")
+
+	// 先检查每日上限，避免达到上限后仍设置发送间隔
+	if m.opts.DailyLimit > 0 {
+		val, err := m.cache.Get(ctx, daily)
+		if err != nil {
+			return error_codes.NewWithError(error_codes.CacheErr, "", err)
+		}
+		if cast.ToInt64(val) >= int64(m.opts.DailyLimit) {
+			return limitExceeded(tomorrow.Sub(now))
+		}
+	}
+
+	if m.opts.Cooldown > 0 {
+		ok, err := atomic.SetNX(ctx, cooldownKey+id, 1, m.opts.Cooldown)
+		if err != nil {
+			return error_codes.NewWithError(error_codes.CacheErr, "", err)
+		}
+		if !ok {
+			return limitExceeded(m.opts.Cooldown)
+		}
+	}
+
+	// rollback 撤销发送间隔与每日计数，发送失败时允许立即重试且不占用每日次数
+	counted := false
+	rollback := func() {
+		_ = m.cache.Delete(ctx, cooldownKey+id)
+		if counted {
+			_, _ = atomic.Decr(ctx, daily)
+		}
+	}
+
+	if m.opts.DailyLimit > 0 {
+		n, err := atomic.Incr(ctx, daily, tomorrow.Sub(now))
+		if err != nil {
+			rollback()
+			return error_codes.NewWithError(error_codes.CacheErr, "", err)
+		}
+		counted = true
+		// 并发发送时计数可能超过检查时的值
+		if n > int64(m.opts.DailyLimit) {
+			rollback()
+			return limitExceeded(tomorrow.Sub(now))
+		}
+	}
+
+	code, err := m.generate()
+	if err != nil {
+		rollback()
+		return err
+	}
+
+	// 新验证码覆盖旧验证码，并重置校验次数
+	if err = m.cache.Set(ctx, codeKey+id, code, m.opts.TTL); err != nil {
+		rollback()
+		return error_codes.NewWithError(error_codes.CacheErr, "", err)
+	}
+	if err = m.cache.Delete(ctx, attemptsKey+id); err != nil {
+		rollback()
+		return error_codes.NewWithError(error_codes.CacheErr, "", err)
+	}
+
+	if err = m.sender.Send(ctx, scene, target, code); err != nil {
+		_ = m.cache.Delete(ctx, codeKey+id)
+		rollback()
+		return error_codes.NewWithError(error_codes.ServiceUnavailable, "", err)
+	}
+
+	return nil
+}
+
+// Verify 校验验证码，成功后验证码失效；超过最大校验次数后验证码同样失效
+func (m *Manager) Verify(ctx context.Context, scene, target, code string) error {
+	id := scene + ":" + target
+
+	val, err := m.cache.Get(ctx, codeKey+id)
+	if err != nil {
+		return error_codes.NewWithError(error_codes.CacheErr, "", err)
+	}
+	expected := cast.ToString(val)
+	if expected == "" {
+		return error_codes.InvalidVerifyCodeError
+	}
+
+	if m.opts.MaxAttempts > 0 {
+		atomic, err := cache.AsAtomic(m.cache)
+		if err != nil {
+			return error_codes.NewWithError(error_codes.CacheErr, "", err)
+		}
+		n, err := atomic.Incr(ctx, attemptsKey+id, m.opts.TTL)
+		if err != nil {
+			return error_codes.NewWithError(error_codes.CacheErr, "", err)
+		}
+		if n > int64(m.opts.MaxAttempts) {
+			_ = m.cache.Delete(ctx, codeKey+id, attemptsKey+id)
+			return error_codes.InvalidVerifyCodeError
+		}
+	}
+
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) != 1 {
+		return error_codes.InvalidVerifyCodeError
+	}
+
+	// 并发校验时只有取到验证码的请求成功
+	val, err = m.cache.GetDel(ctx, codeKey+id)
+	if err != nil {
+		return error_codes.NewWithError(error_codes.CacheErr, "", err)
+	}
+	if cast.ToString(val) != expected {
+		return error_codes.InvalidVerifyCodeError
+	}
+	_ = m.cache.Delete(ctx, attemptsKey+id)
+
+	return nil
+}
+
+// limitExceeded 返回携带重试间隔的 LimitExceed 错误
+func limitExceeded(retryDelay time.Duration) error {
+	e := error_codes.NewWithCode(error_codes.LimitExceed).(*error_codes.CustomError)
+	return e.WithDetails(error_codes.RetryInfo{RetryDelay: retryDelay})
+}
+
+func (m *Manager) generate() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < m.opts.Digits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", m.opts.Digits, n), nil
+}
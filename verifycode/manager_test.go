@@ -0,0 +1,235 @@
+package verifycode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xiaWave/go-common-module/cache"
+	"github.com/xiaWave/go-common-module/error_codes"
+)
+
+// memCache 测试用的内存 cache.Cache，不处理过期
+type memCache struct {
+	mu    sync.Mutex
+	items map[string]string
+}
+
+func newMemCache() *memCache {
+	return &memCache{items: make(map[string]string)}
+}
+
+func (c *memCache) Set(ctx context.Context, key string, val interface{}, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = fmt.Sprint(val)
+	return nil
+}
+
+func (c *memCache) SetNX(ctx context.Context, key string, val interface{}, expiration time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.items[key]; ok {
+		return false, nil
+	}
+	c.items[key] = fmt.Sprint(val)
+	return true, nil
+}
+
+func (c *memCache) add(key string, delta int64) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var n int64
+	if v, ok := c.items[key]; ok {
+		if _, err := fmt.Sscan(v, &n); err != nil {
+			return 0, err
+		}
+	}
+	n += delta
+	c.items[key] = fmt.Sprint(n)
+	return n, nil
+}
+
+func (c *memCache) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	return c.add(key, 1)
+}
+
+func (c *memCache) Decr(ctx context.Context, key string) (int64, error) {
+	return c.add(key, -1)
+}
+
+func (c *memCache) CompareAndDelete(ctx context.Context, key string, val string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok := c.items[key]; !ok || v != val {
+		return false, nil
+	}
+	delete(c.items, key)
+	return true, nil
+}
+
+func (c *memCache) Get(ctx context.Context, key string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.items[key], nil
+}
+
+func (c *memCache) GetDel(ctx context.Context, key string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v := c.items[key]
+	delete(c.items, key)
+	return v, nil
+}
+
+func (c *memCache) Scan(ctx context.Context, key string, val interface{}) error {
+	return errors.New("not implemented")
+}
+
+func (c *memCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.items, key)
+	}
+	return nil
+}
+
+func (c *memCache) Options() cache.Options          { return cache.Options{} }
+func (c *memCache) Ping(ctx context.Context) error  { return nil }
+func (c *memCache) Close(ctx context.Context) error { return nil }
+
+func TestSendAndVerify(t *testing.T) {
+	ctx := context.Background()
+	sender := NewFakeSender()
+	m := NewManager(newMemCache(), sender)
+
+	if err := m.Send(ctx, "login", "13800000000"); err != nil {
+		t.Fatal(err)
+	}
+	code, ok := sender.Last("login", "13800000000")
+	if !ok || len(code) != 6 {
+		t.Fatalf("sent code = %q, want 6 digits", code)
+	}
+
+	if err := m.Verify(ctx, "register", "13800000000", code); !errors.Is(err, error_codes.InvalidVerifyCodeError) {
+		t.Fatalf("verify other scene: err = %v, want VerifyCodeInvalid", err)
+	}
+	if err := m.Verify(ctx, "login", "13800000000", code); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err := m.Verify(ctx, "login", "13800000000", code); !errors.Is(err, error_codes.InvalidVerifyCodeError) {
+		t.Fatalf("verify twice: err = %v, want VerifyCodeInvalid", err)
+	}
+}
+
+func TestSendEmptyTarget(t *testing.T) {
+	m := NewManager(newMemCache(), NewFakeSender())
+	if err := m.Send(context.Background(), "login", ""); error_codes.Code(err) != error_codes.PhoneEmpty {
+		t.Fatalf("err = %v, want PhoneEmpty", err)
+	}
+}
+
+func TestSendCooldown(t *testing.T) {
+	ctx := context.Background()
+	sender := NewFakeSender()
+	m := NewManager(newMemCache(), sender, WithCooldown(time.Minute))
+
+	if err := m.Send(ctx, "login", "a@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	err := m.Send(ctx, "login", "a@example.com")
+	var ce *error_codes.CustomError
+	if !errors.As(err, &ce) || ce.Code != error_codes.LimitExceed {
+		t.Fatalf("err = %v, want LimitExceed", err)
+	}
+	if len(ce.Details) != 1 || ce.Details[0].(error_codes.RetryInfo).RetryDelay != time.Minute {
+		t.Fatalf("details = %+v, want RetryInfo of 1m", ce.Details)
+	}
+	if sender.Count() != 1 {
+		t.Fatalf("sent %d codes, want 1", sender.Count())
+	}
+}
+
+func TestVerifyMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	sender := NewFakeSender()
+	m := NewManager(newMemCache(), sender, WithMaxAttempts(2))
+
+	if err := m.Send(ctx, "login", "13800000000"); err != nil {
+		t.Fatal(err)
+	}
+	code, _ := sender.Last("login", "13800000000")
+
+	for i := 0; i < 2; i++ {
+		if err := m.Verify(ctx, "login", "13800000000", "wrong"); !errors.Is(err, error_codes.InvalidVerifyCodeError) {
+			t.Fatalf("attempt %d: err = %v, want VerifyCodeInvalid", i, err)
+		}
+	}
+	// 超过最大校验次数后正确的验证码也已失效
+	if err := m.Verify(ctx, "login", "13800000000", code); !errors.Is(err, error_codes.InvalidVerifyCodeError) {
+		t.Fatalf("err = %v, want VerifyCodeInvalid", err)
+	}
+}
+
+func TestSendDailyLimit(t *testing.T) {
+	ctx := context.Background()
+	sender := NewFakeSender()
+	m := NewManager(newMemCache(), sender, WithCooldown(0), WithDailyLimit(2))
+
+	for i := 0; i < 2; i++ {
+		if err := m.Send(ctx, "login", "13800000000"); err != nil {
+			t.Fatalf("send %d: %v", i, err)
+		}
+	}
+	if err := m.Send(ctx, "login", "13800000000"); error_codes.Code(err) != error_codes.LimitExceed {
+		t.Fatalf("err = %v, want LimitExceed", err)
+	}
+	if sender.Count() != 2 {
+		t.Fatalf("sent %d codes, want 2", sender.Count())
+	}
+}
+
+func TestSendDailyLimitDoesNotSetCooldown(t *testing.T) {
+	ctx := context.Background()
+	c := newMemCache()
+	m := NewManager(c, NewFakeSender(), WithCooldown(time.Minute), WithDailyLimit(1))
+
+	if err := m.Send(ctx, "login", "13800000000"); err != nil {
+		t.Fatal(err)
+	}
+	_ = c.Delete(ctx, cooldownKey+"login:13800000000")
+
+	if err := m.Send(ctx, "login", "13800000000"); error_codes.Code(err) != error_codes.LimitExceed {
+		t.Fatalf("err = %v, want LimitExceed", err)
+	}
+	if v, _ := c.Get(ctx, cooldownKey+"login:13800000000"); v != "" {
+		t.Fatal("cooldown set after daily limit rejected the send")
+	}
+}
+
+func TestSendFailureRestoresQuota(t *testing.T) {
+	ctx := context.Background()
+	c := newMemCache()
+	failing := SenderFunc(func(ctx context.Context, scene, target, code string) error {
+		return errors.New("sms provider unavailable")
+	})
+
+	m := NewManager(c, failing, WithCooldown(time.Minute), WithDailyLimit(1))
+	if err := m.Send(ctx, "login", "13800000000"); error_codes.Code(err) != error_codes.ServiceUnavailable {
+		t.Fatalf("err = %v, want ServiceUnavailable", err)
+	}
+
+	// 发送失败不占用发送间隔与每日次数
+	sender := NewFakeSender()
+	m = NewManager(c, sender, WithCooldown(time.Minute), WithDailyLimit(1))
+	if err := m.Send(ctx, "login", "13800000000"); err != nil {
+		t.Fatalf("retry after failure: %v", err)
+	}
+	if sender.Count() != 1 {
+		t.Fatalf("sent %d codes, want 1", sender.Count())
+	}
+}
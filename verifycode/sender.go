@@ -0,0 +1,67 @@
+package verifycode
+
+import (
+	"context"
+	"sync"
+
+	"github.com/xiaWave/go-common-module/logger"
+)
+
+// Sender 发送验证码，如短信、邮件
+type Sender interface {
+	Send(ctx context.Context, scene, target, code string) error
+}
+
+// SenderFunc 函数形式的 Sender
+type SenderFunc func(ctx context.Context, scene, target, code string) error
+
+func (f SenderFunc) Send(ctx context.Context, scene, target, code string) error {
+	return f(ctx, scene, target, code)
+}
+
+// LogSender 只将验证码输出到日志，用于开发环境
+type LogSender struct {
+	Logger logger.Logger
+}
+
+func (s LogSender) Send(ctx context.Context, scene, target, code string) error {
+	s.Logger.WithFields(logger.Fields{"scene": scene, "target": target}).Infof(ctx, "verification code: %s", code)
+	return nil
+}
+
+// FakeSender 在内存中记录发送过的验证码，用于测试
+type FakeSender struct {
+	mu    sync.Mutex
+	codes map[string]string
+	count int
+}
+
+func NewFakeSender() *FakeSender {
+	return &FakeSender{codes: make(map[string]string)}
+}
+
+func (s *FakeSender) Send(ctx context.Context, scene, target, code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.codes[scene+":"+target] = code
+	s.count++
+	return nil
+}
+
+// Last 返回最近一次发送给 target 的验证码
+func (s *FakeSender) Last(scene, target string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code, ok := s.codes[scene+":"+target]
+	return code, ok
+}
+
+// Count 返回发送次数
+func (s *FakeSender) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.count
+}